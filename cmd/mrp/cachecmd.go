@@ -0,0 +1,153 @@
+package main
+
+import (
+    "flag"
+    "fmt"
+    "os"
+    "strconv"
+    "strings"
+    "text/tabwriter"
+    "time"
+
+    "github.com/zudsniper/meet-recording-processor/internal/cache"
+)
+
+// runCacheCmd implements `mrp cache ls` and `mrp cache prune`. Returns the exit code.
+func runCacheCmd(args []string) int {
+    usage := func() {
+        fmt.Fprintln(os.Stderr, "usage: mrp cache ls [--dir DIR]")
+        fmt.Fprintln(os.Stderr, "       mrp cache prune [--dir DIR] [--max-age 30d] [--max-size 2GB]")
+    }
+    if len(args) == 0 {
+        usage()
+        return 2
+    }
+    defDir, err := cache.DefaultDir()
+    if err != nil {
+        fail("%v", err)
+        return 1
+    }
+
+    fs := flag.NewFlagSet("cache "+args[0], flag.ContinueOnError)
+    dir := fs.String("dir", defDir, "Cache directory (or MRP_CACHE_DIR)")
+    maxAge := fs.String("max-age", "", "Remove entries unused for longer than this, e.g. 30d or 12h")
+    maxSize := fs.String("max-size", "", "Evict least recently used entries until the cache fits, e.g. 500MB or 2GB")
+
+    switch args[0] {
+    case "ls", "list":
+        if err := fs.Parse(args[1:]); err != nil {
+            return 2
+        }
+        infos, err := cache.Store{Dir: *dir}.List()
+        if err != nil {
+            fail("list cache: %v", err)
+            return 1
+        }
+        if len(infos) == 0 {
+            info("Cache is empty (%s)", *dir)
+            return 0
+        }
+        tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+        fmt.Fprintln(tw, "KEY\tSIZE\tLAST USED\tAUDIO\tSETTINGS")
+        var total int64
+        for _, in := range infos {
+            total += in.Size
+            fmt.Fprintf(tw, "%s\t%s\t%s ago\t%s\t%s\n", shortKey(in.Key), formatBytes(in.Size), time.Since(in.LastUsed).Round(time.Minute), in.Audio, in.Settings)
+        }
+        tw.Flush()
+        info("%d entries, %s in %s", len(infos), formatBytes(total), *dir)
+        return 0
+    case "prune":
+        if err := fs.Parse(args[1:]); err != nil {
+            return 2
+        }
+        age, err := parseAge(*maxAge)
+        if err != nil {
+            fail("--max-age: %v", err)
+            return 2
+        }
+        size, err := parseBytes(*maxSize)
+        if err != nil {
+            fail("--max-size: %v", err)
+            return 2
+        }
+        if age == 0 && size == 0 {
+            fail("prune needs --max-age and/or --max-size")
+            return 2
+        }
+        removed, err := cache.Store{Dir: *dir}.Prune(age, size)
+        var freed int64
+        for _, in := range removed {
+            freed += in.Size
+        }
+        if err != nil {
+            fail("prune cache: %v", err)
+            return 1
+        }
+        ok("Removed %d entries (%s)", len(removed), formatBytes(freed))
+        return 0
+    default:
+        usage()
+        return 2
+    }
+}
+
+// parseAge accepts Go durations plus a "d" suffix for days.
+func parseAge(s string) (time.Duration, error) {
+    s = strings.TrimSpace(s)
+    if s == "" {
+        return 0, nil
+    }
+    if days, found := strings.CutSuffix(s, "d"); found {
+        n, err := strconv.ParseFloat(days, 64)
+        if err != nil {
+            return 0, fmt.Errorf("invalid age %q", s)
+        }
+        return time.Duration(n * float64(24*time.Hour)), nil
+    }
+    return time.ParseDuration(s)
+}
+
+// parseBytes accepts plain byte counts or KB/MB/GB suffixes (powers of 1024).
+func parseBytes(s string) (int64, error) {
+    s = strings.ToUpper(strings.TrimSpace(s))
+    if s == "" {
+        return 0, nil
+    }
+    mult := int64(1)
+    for _, u := range []struct {
+        suffix string
+        mult   int64
+    }{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}} {
+        if v, found := strings.CutSuffix(s, u.suffix); found {
+            s, mult = strings.TrimSpace(v), u.mult
+            break
+        }
+    }
+    n, err := strconv.ParseFloat(s, 64)
+    if err != nil || n < 0 {
+        return 0, fmt.Errorf("invalid size %q", s)
+    }
+    return int64(n * float64(mult)), nil
+}
+
+func formatBytes(n int64) string {
+    switch {
+    case n >= 1<<30:
+        return fmt.Sprintf("%.1fGB", float64(n)/(1<<30))
+    case n >= 1<<20:
+        return fmt.Sprintf("%.1fMB", float64(n)/(1<<20))
+    case n >= 1<<10:
+        return fmt.Sprintf("%.1fKB", float64(n)/(1<<10))
+    default:
+        return fmt.Sprintf("%dB", n)
+    }
+}
+
+// shortKey abbreviates a cache key for display.
+func shortKey(k string) string {
+    if len(k) > 12 {
+        return k[:12]
+    }
+    return k
+}
@@ -2,15 +2,21 @@ package main
 
 import (
     "context"
+    "errors"
     "flag"
     "fmt"
+    "io"
+    "math"
     "os"
     "os/exec"
     "path/filepath"
+    "strconv"
     "strings"
     "time"
 
+    "github.com/zudsniper/meet-recording-processor/internal/cache"
     "github.com/zudsniper/meet-recording-processor/internal/config"
+    "github.com/zudsniper/meet-recording-processor/internal/correct"
     "github.com/zudsniper/meet-recording-processor/internal/diarize"
     "github.com/zudsniper/meet-recording-processor/internal/media"
     "github.com/zudsniper/meet-recording-processor/internal/output"
@@ -63,52 +69,187 @@ func main() {
     // Load env early so flags that default to env pick up values from ~/.mrp.env
     config.LoadDefaultEnv()
 
+    if len(os.Args) > 1 && os.Args[1] == "cache" {
+        os.Exit(runCacheCmd(os.Args[2:]))
+    }
+
     var (
         inPath    string
         outPath   string
         backend   string
         model     string
+        task      string
         tmpDir    string
         diarizer  string
         eventTitle string
         eventDesc  string
         attendees stringSlice
+        glossary  string
+        fixes     string
 
-        openaiAPIKey string
-        openaiModel  string
+        preprocess  string
+        trimSilence bool
+        maxSilence  time.Duration
+        silenceDB   float64
+        clipStart   string
+        clipEnd     string
+        wordTimings bool
+        audioStream int
+        channel     string
+        perTrack    string
+        trackNames  stringSlice
+
+        openaiAPIKey  string
+        openaiModel   string
+        openaiBaseURL string
+        openaiHeaders = map[string]string{}
 
         cfAccountID string
         cfAPIToken  string
         cfModel     string
+        cfTask      string
+        cfLanguage  string
+        cfVAD       bool
+        cfPrompt    string
 
         localModel   string
         localDevice  string
+        localCompute string
+        localLang    string
+        localBeam    int
+        localVAD     bool
+        localPrompt  string
+        localWords   bool
+
+        dgAPIKey      string
+        dgBaseURL     string
+        dgModel       string
+        dgLanguage    string
+        dgDiarize     bool
+        dgSmartFormat bool
+        dgUtterances  bool
+
+        aaiAPIKey   string
+        aaiBaseURL  string
+        aaiModel    string
+        aaiLanguage string
+        aaiSpeakers bool
+        pollEvery   time.Duration
+
+        pluginOpts = map[string]string{}
+
+        wcppBin      string
+        wcppModel    string
+        wcppThreads  int
+        wcppLanguage string
+
+        chunkMax     time.Duration
+        chunkOverlap time.Duration
+        concurrency  int
+        retryMax     int
+        retryBase    time.Duration
+
+        noCache      bool
+        refreshCache bool
+        cacheDir     string
 
         showVersion bool
+        listPlugins bool
     )
 
     flag.StringVar(&inPath, "input", "", "Input video file path (-i)")
     flag.StringVar(&inPath, "i", "", "Input video file path")
     flag.StringVar(&outPath, "output", "", "Output transcript markdown file (-o)")
     flag.StringVar(&outPath, "o", "", "Output transcript markdown file")
-    flag.StringVar(&backend, "backend", "openai", "Transcription backend: openai|cloudflare|local")
+    flag.StringVar(&backend, "backend", "openai", "Transcription backend: openai|cloudflare|local|whispercpp|deepgram|assemblyai or an mrp-backend-<name> plugin, or a comma-separated fallback chain")
     flag.StringVar(&model, "model", "", "Generic model name override (backend-specific)")
+    flag.StringVar(&task, "task", transcribe.TaskTranscribe, "transcribe (keep the spoken language) or translate (English text; openai, cloudflare, local and plugins)")
     flag.StringVar(&tmpDir, "tmpdir", "", "Temporary working directory (default system temp)")
     flag.StringVar(&diarizer, "diarization", "none", "Diarization: none|silence")
+    flag.StringVar(&preprocess, "preprocess", "none", "Audio cleanup before transcription: a preset ("+strings.Join(media.PresetNames(), "|")+") or a chain like highpass,afftdn,loudnorm")
+    flag.BoolVar(&trimSilence, "trim-silence", false, "Skip silence before the first and after the last speech (timestamps stay relative to the original)")
+    flag.DurationVar(&maxSilence, "max-silence", 0, "Shorten pauses longer than this, e.g. 30s (default: keep all pauses)")
+    flag.Float64Var(&silenceDB, "silence-db", 0, "Level in dBFS below which audio counts as silence, e.g. -45 (default: adapt to the noise floor)")
+    flag.BoolVar(&wordTimings, "word-timings", false, "Append a section listing every word with its start time (backends that report word timings; turns on --local-word-timestamps)")
+    flag.StringVar(&clipStart, "start", "", "Only transcribe from this point, HH:MM:SS (timestamps stay relative to the original)")
+    flag.StringVar(&clipEnd, "end", "", "Only transcribe up to this point, HH:MM:SS")
+    flag.IntVar(&audioStream, "audio-stream", -1, "Audio stream to transcribe, counting audio streams from 0 (default: the file's default track)")
+    flag.StringVar(&channel, "channel", "", "Transcribe one channel only: left|right|N (0-based; default: mix all channels)")
+    flag.StringVar(&perTrack, "per-track", "", "Transcribe each audio stream or channel separately and label speakers by track: streams|channels")
+    flag.Var(&trackNames, "track-names", "Speaker names for --per-track, in track order (repeatable or comma-separated)")
 
     flag.StringVar(&eventTitle, "title", "", "Event title metadata")
     flag.StringVar(&eventDesc, "description", "", "Event description metadata")
     flag.Var(&attendees, "attendee", "Attendee name (repeatable or comma-separated)")
+    flag.StringVar(&fixes, "corrections", "", "Dictionary of 'wrong => right' replacements and canonical terms applied after transcription")
+    flag.StringVar(&glossary, "glossary", "", "File of names and terms, one per line, used to bias recognition (attendees are added automatically)")
 
     flag.StringVar(&openaiAPIKey, "openai-api-key", os.Getenv("OPENAI_API_KEY"), "OpenAI API key (or set OPENAI_API_KEY, or in ~/.mrp.env)")
     flag.StringVar(&openaiModel, "openai-model", "gpt-4o-mini-transcribe", "OpenAI transcription model")
+    flag.StringVar(&openaiBaseURL, "openai-base-url", os.Getenv("OPENAI_BASE_URL"), "OpenAI-compatible API root, e.g. http://localhost:8000/v1 (or OPENAI_BASE_URL)")
+    flag.Func("openai-header", "Extra header for the OpenAI backend as 'Name: value' (repeatable)", func(v string) error {
+        name, val, found := strings.Cut(v, ":")
+        if !found || strings.TrimSpace(name) == "" {
+            return fmt.Errorf("expected 'Name: value', got %q", v)
+        }
+        openaiHeaders[strings.TrimSpace(name)] = strings.TrimSpace(val)
+        return nil
+    })
 
     flag.StringVar(&cfAccountID, "cf-account-id", os.Getenv("CF_ACCOUNT_ID"), "Cloudflare Account ID (or CF_ACCOUNT_ID, or in ~/.mrp.env)")
     flag.StringVar(&cfAPIToken, "cf-api-token", os.Getenv("CF_API_TOKEN"), "Cloudflare API Token (or CF_API_TOKEN, or in ~/.mrp.env)")
     flag.StringVar(&cfModel, "cf-model", "@cf/openai/whisper", "Cloudflare AI model identifier")
+    flag.StringVar(&cfTask, "cf-task", "", "Cloudflare task override: transcribe|translate (whisper-large-v3-turbo and newer; default follows --task)")
+    flag.StringVar(&cfLanguage, "cf-language", "", "Spoken language hint for Cloudflare, e.g. en (whisper-large-v3-turbo and newer)")
+    flag.BoolVar(&cfVAD, "cf-vad", false, "Enable Cloudflare's VAD filter (whisper-large-v3-turbo and newer)")
+    flag.StringVar(&cfPrompt, "cf-initial-prompt", "", "Initial prompt for Cloudflare (whisper-large-v3-turbo and newer)")
 
     flag.StringVar(&localModel, "local-model", "base.en", "faster-whisper model name or path (e.g., base.en, medium, or local path)")
     flag.StringVar(&localDevice, "local-device", "auto", "Device for local model: auto|cpu|cuda (default respects MRP_DEFAULT_LOCAL_DEVICE)")
+    flag.StringVar(&localCompute, "local-compute-type", "auto", "Compute type for local model: auto|int8|int8_float16|float16|float32 (auto: float16 on cuda, int8 on cpu)")
+    flag.StringVar(&localLang, "local-language", "", "Spoken language for the local model, e.g. en (default auto-detect)")
+    flag.IntVar(&localBeam, "local-beam-size", 0, "Beam size for local decoding (default: faster-whisper's default of 5)")
+    flag.BoolVar(&localVAD, "local-vad", false, "Filter out non-speech with faster-whisper's built-in VAD")
+    flag.StringVar(&localPrompt, "local-initial-prompt", "", "Initial prompt for the local model, e.g. product names or jargon")
+    flag.BoolVar(&localWords, "local-word-timestamps", false, "Compute per-word timings with the local model (slower)")
+    flag.StringVar(&dgAPIKey, "deepgram-api-key", os.Getenv("DEEPGRAM_API_KEY"), "Deepgram API key (or DEEPGRAM_API_KEY, or in ~/.mrp.env)")
+    flag.StringVar(&dgBaseURL, "deepgram-base-url", os.Getenv("DEEPGRAM_BASE_URL"), "Deepgram API root (default https://api.deepgram.com, or DEEPGRAM_BASE_URL)")
+    flag.StringVar(&dgModel, "deepgram-model", "nova-3", "Deepgram model")
+    flag.StringVar(&dgLanguage, "deepgram-language", "", "Spoken language for Deepgram, e.g. en or multi (default: Deepgram's default)")
+    flag.BoolVar(&dgDiarize, "deepgram-diarize", true, "Label Deepgram utterances by speaker")
+    flag.BoolVar(&dgSmartFormat, "deepgram-smart-format", true, "Apply Deepgram smart formatting (punctuation, numerals, dates)")
+    flag.BoolVar(&dgUtterances, "deepgram-utterances", true, "Split the Deepgram transcript into utterances (needed for timestamps per segment)")
+
+    flag.StringVar(&aaiAPIKey, "assemblyai-api-key", os.Getenv("ASSEMBLYAI_API_KEY"), "AssemblyAI API key (or ASSEMBLYAI_API_KEY, or in ~/.mrp.env)")
+    flag.StringVar(&aaiBaseURL, "assemblyai-base-url", os.Getenv("ASSEMBLYAI_BASE_URL"), "AssemblyAI API root (default https://api.assemblyai.com, or ASSEMBLYAI_BASE_URL)")
+    flag.StringVar(&aaiModel, "assemblyai-model", "", "AssemblyAI speech model, e.g. best or nano (default: service default)")
+    flag.StringVar(&aaiLanguage, "assemblyai-language", "", "Spoken language for AssemblyAI, e.g. en (default auto-detect)")
+    flag.BoolVar(&aaiSpeakers, "assemblyai-speaker-labels", true, "Label AssemblyAI utterances by speaker")
+    flag.DurationVar(&pollEvery, "poll-interval", transcribe.DefaultAsyncOptions.PollInterval, "Initial polling interval for job-based backends (backs off to 30s)")
+
+    flag.StringVar(&wcppBin, "whispercpp-bin", envOr("MRP_WHISPERCPP_BIN", "whisper-cli"), "whisper.cpp CLI binary name or path (or MRP_WHISPERCPP_BIN)")
+    flag.StringVar(&wcppModel, "whispercpp-model", os.Getenv("MRP_WHISPERCPP_MODEL"), "Path to a whisper.cpp GGML model, e.g. ggml-base.en.bin (or MRP_WHISPERCPP_MODEL)")
+    flag.IntVar(&wcppThreads, "whispercpp-threads", 0, "Threads for whisper.cpp (default: binary default)")
+    flag.StringVar(&wcppLanguage, "whispercpp-language", "auto", "Spoken language for whisper.cpp, e.g. en (default auto-detect)")
+
+    flag.Func("plugin-opt", "Option passed to plugin backends as 'key=value' (repeatable)", func(v string) error {
+        k, val, found := strings.Cut(v, "=")
+        if !found || strings.TrimSpace(k) == "" {
+            return fmt.Errorf("expected 'key=value', got %q", v)
+        }
+        pluginOpts[strings.TrimSpace(k)] = val
+        return nil
+    })
+    flag.BoolVar(&listPlugins, "list-plugins", false, "List discovered mrp-backend-<name> plugins and exit")
+
+    flag.DurationVar(&chunkMax, "chunk-max", 0, "Maximum chunk length for remote backends, e.g. 10m (default: derived from the backend's upload limit)")
+    flag.DurationVar(&chunkOverlap, "chunk-overlap", 2*time.Second, "Audio overlap between consecutive chunks")
+    flag.IntVar(&concurrency, "concurrency", 4, "Number of chunks transcribed in parallel by remote backends")
+    flag.IntVar(&retryMax, "retry-max", transcribe.DefaultRetryPolicy.MaxAttempts, "Maximum attempts per request for HTTP backends (1 disables retries)")
+    flag.DurationVar(&retryBase, "retry-base", transcribe.DefaultRetryPolicy.BaseDelay, "Initial retry backoff for HTTP backends; doubles on each attempt")
+    flag.BoolVar(&noCache, "no-cache", false, "Neither read nor write the transcript cache")
+    flag.BoolVar(&refreshCache, "refresh", false, "Ignore cached transcripts and overwrite them with fresh results")
+    flag.StringVar(&cacheDir, "cache-dir", "", "Transcript cache directory (default $MRP_CACHE_DIR or ~/.mrp/cache)")
     flag.BoolVar(&showVersion, "version", false, "Print mrp version and exit")
 
     flag.Parse()
@@ -117,95 +258,393 @@ func main() {
         fmt.Println(version.Version)
         return
     }
+    if listPlugins {
+        for _, n := range transcribe.ListPlugins() {
+            p, _ := transcribe.FindPlugin(n)
+            fmt.Printf("%s\t%s\n", n, p)
+        }
+        return
+    }
 
     // If flags not provided, re-read env in case the shell didn’t source ~/.mrp.env
     if openaiAPIKey == "" {
         openaiAPIKey = os.Getenv("OPENAI_API_KEY")
     }
+    if openaiBaseURL == "" {
+        openaiBaseURL = os.Getenv("OPENAI_BASE_URL")
+    }
     if cfAccountID == "" {
         cfAccountID = os.Getenv("CF_ACCOUNT_ID")
     }
     if cfAPIToken == "" {
         cfAPIToken = os.Getenv("CF_API_TOKEN")
     }
+    if dgAPIKey == "" {
+        dgAPIKey = os.Getenv("DEEPGRAM_API_KEY")
+    }
+    if dgBaseURL == "" {
+        dgBaseURL = os.Getenv("DEEPGRAM_BASE_URL")
+    }
+    if aaiAPIKey == "" {
+        aaiAPIKey = os.Getenv("ASSEMBLYAI_API_KEY")
+    }
+    if aaiBaseURL == "" {
+        aaiBaseURL = os.Getenv("ASSEMBLYAI_BASE_URL")
+    }
 
-    if inPath == "" {
+    inputs := flag.Args()
+    if inPath != "" {
+        inputs = append([]string{inPath}, inputs...)
+    }
+    if len(inputs) == 0 {
         fail("missing --input/-i video path")
         os.Exit(2)
     }
-    if outPath == "" {
-        base := strings.TrimSuffix(filepath.Base(inPath), filepath.Ext(inPath))
-        outPath = base + ".md"
+    if outPath != "" && len(inputs) > 1 {
+        fail("--output/-o can only be used with a single input")
+        os.Exit(2)
     }
 
-    // Prepare context
+    // Setup context (venv bootstrap etc.); each input gets its own timeout below
     ctx, cancel := context.WithTimeout(context.Background(), 2*time.Hour)
     defer cancel()
+    var err error
 
-    // Step 1: extract audio
-    info("Extracting audio via ffmpeg...")
-    audioPath, err := media.ExtractAudio(ctx, inPath, tmpDir)
-    if err != nil {
-        fail("audio extraction failed: %v", err)
-        os.Exit(1)
+    // Vocabulary hints: attendee names first, then the glossary file. Each backend trims
+    // them to the Whisper prompt budget.
+    var terms []string
+    if glossary != "" {
+        terms, err = transcribe.LoadGlossary(glossary)
+        if err != nil {
+            fail("read glossary: %v", err)
+            os.Exit(2)
+        }
+    }
+    terms = transcribe.MergeTerms(attendees, terms)
+    termPrompt, fit := transcribe.GlossaryPrompt("", terms, transcribe.WhisperPromptTokens)
+    if fit < len(terms) {
+        warn("glossary too long for the prompt budget; using the first %d of %d terms", fit, len(terms))
+    }
+    var dict *correct.Dictionary
+    if fixes != "" {
+        dict, err = correct.Load(fixes)
+        if err != nil {
+            fail("read corrections: %v", err)
+            os.Exit(2)
+        }
     }
-    ok("Audio ready: %s", audioPath)
 
-    // Step 2: pick backend
+    // Pick backend(s); remote backends are wrapped so long audio is split to fit their upload limits.
+    // A comma-separated list forms a fallback chain tried in order.
     var be transcribe.Backend
-    switch strings.ToLower(backend) {
-    case "openai":
-        if openaiAPIKey == "" {
-            fail("OpenAI backend selected but API key is missing")
-            os.Exit(1)
+    var names []string
+    for _, n := range strings.Split(backend, ",") {
+        if n = strings.ToLower(strings.TrimSpace(n)); n != "" {
+            names = append(names, n)
         }
-        if model != "" {
-            openaiModel = model
+    }
+    if len(names) == 0 {
+        fail("no backend selected")
+        os.Exit(2)
+    }
+    for _, n := range names {
+        if _, isPlugin := transcribe.FindPlugin(n); !knownBackends[n] && !isPlugin {
+            fail("unknown backend: %s (no built-in backend or mrp-backend-%s plugin)", n, n)
+            os.Exit(2)
         }
-        be = transcribe.NewOpenAIBackend(openaiAPIKey, openaiModel)
-    case "cloudflare":
-        if cfAccountID == "" || cfAPIToken == "" {
-            fail("Cloudflare backend requires cf-account-id and cf-api-token")
-            os.Exit(1)
+    }
+    if task != transcribe.TaskTranscribe && task != transcribe.TaskTranslate {
+        fail("invalid --task %q (want transcribe or translate)", task)
+        os.Exit(2)
+    }
+    translate := task == transcribe.TaskTranslate
+    // --model is a generic override and only meaningful for a single backend
+    single := len(names) == 1
+    if model != "" && !single {
+        warn("--model is ignored with a backend chain; use the backend-specific model flags")
+        model = ""
+    }
+    retry := transcribe.DefaultRetryPolicy
+    retry.MaxAttempts = retryMax
+    retry.BaseDelay = retryBase
+    retry.OnRetry = func(attempt int, delay time.Duration, err error) {
+        warn("attempt %d failed (%v); retrying in %s", attempt, err, delay.Round(100*time.Millisecond))
+    }
+    chunkOpts := transcribe.ChunkOptions{MaxDuration: chunkMax, Overlap: chunkOverlap, Concurrency: concurrency, TmpDir: tmpDir}
+
+    // The local backend needs a Python environment with faster-whisper, which may mean a
+    // venv and pip install. A fallback chain only does that once the member is reached.
+    setupLocal := func() error {
+        py, err := ensureLocalFasterWhisper(ctx)
+        if err != nil {
+            return fmt.Errorf("local backend setup failed: %w", err)
         }
-        if model != "" {
-            cfModel = model
+        if py != "" {
+            os.Setenv("MRP_PY", py)
         }
-        be = transcribe.NewCloudflareBackend(cfAccountID, cfAPIToken, cfModel)
-    case "local":
-        if model != "" {
-            localModel = model
+        return nil
+    }
+
+    buildBackend := func(name string) (transcribe.Backend, error) {
+        switch name {
+        case "openai":
+            // Self-hosted compatible servers usually run without auth; only OpenAI/Azure need a key.
+            if openaiAPIKey == "" && len(openaiHeaders) == 0 && transcribe.IsOfficialOpenAIURL(openaiBaseURL) {
+                return nil, fmt.Errorf("OpenAI backend selected but API key is missing")
+            }
+            if model != "" {
+                openaiModel = model
+            }
+            // OpenAI only serves translations with whisper-1.
+            if translate && strings.HasPrefix(openaiModel, "gpt-") && transcribe.IsOfficialOpenAIURL(openaiBaseURL) {
+                warn("%s cannot translate; using whisper-1", openaiModel)
+                openaiModel = "whisper-1"
+            }
+            oa, err := transcribe.NewOpenAIBackend(transcribe.OpenAIOptions{
+                APIKey:  openaiAPIKey,
+                Model:   openaiModel,
+                BaseURL: openaiBaseURL,
+                Headers: openaiHeaders,
+                Task:    task,
+                Prompt:  termPrompt,
+                Retry:   retry,
+            })
+            if err != nil {
+                return nil, err
+            }
+            return transcribe.NewChunkedBackend(oa, chunkOpts), nil
+        case "cloudflare":
+            if cfAccountID == "" || cfAPIToken == "" {
+                return nil, fmt.Errorf("Cloudflare backend requires cf-account-id and cf-api-token")
+            }
+            if model != "" {
+                cfModel = model
+            }
+            if cfTask == "" && translate {
+                cfTask = transcribe.TaskTranslate
+            }
+            cfPrompt, _ = transcribe.GlossaryPrompt(cfPrompt, terms, transcribe.WhisperPromptTokens)
+            cf := transcribe.NewCloudflareBackend(transcribe.CloudflareOptions{
+                AccountID:     cfAccountID,
+                APIToken:      cfAPIToken,
+                Model:         cfModel,
+                Task:          cfTask,
+                Language:      cfLanguage,
+                VADFilter:     cfVAD,
+                InitialPrompt: cfPrompt,
+                Retry:         retry,
+            })
+            return transcribe.NewChunkedBackend(cf, chunkOpts), nil
+        case "local":
+            if model != "" {
+                localModel = model
+            }
+            if translate && strings.HasSuffix(localModel, ".en") {
+                return nil, fmt.Errorf("English-only model %s cannot translate; pick a multilingual one, e.g. --local-model small", localModel)
+            }
+            // Respect env default for device if user did not choose
+            if strings.ToLower(localDevice) == "auto" {
+                if envDev := strings.ToLower(strings.TrimSpace(os.Getenv("MRP_DEFAULT_LOCAL_DEVICE"))); envDev == "cpu" || envDev == "cuda" {
+                    localDevice = envDev
+                } else if envDev2 := strings.ToLower(strings.TrimSpace(os.Getenv("MRP_LOCAL_DEVICE"))); envDev2 == "cpu" || envDev2 == "cuda" {
+                    localDevice = envDev2
+                }
+            }
+            // faster-whisper before 1.0.2 has no hotwords; it gets the terms in its prompt,
+            // trimmed here so the start of the prompt is not silently dropped
+            localHotwordsPrompt := ""
+            if termPrompt != "" {
+                localHotwordsPrompt, _ = transcribe.GlossaryPrompt(localPrompt, terms, transcribe.WhisperPromptTokens)
+            }
+            return transcribe.NewFasterWhisperBackend(transcribe.FasterWhisperOptions{
+                Model:          localModel,
+                Device:         localDevice,
+                ComputeType:    localCompute,
+                Language:       localLang,
+                Task:           task,
+                BeamSize:       localBeam,
+                VADFilter:      localVAD,
+                InitialPrompt:  localPrompt,
+                Hotwords:       termPrompt,
+                HotwordsPrompt: localHotwordsPrompt,
+                WordTimestamps: localWords || wordTimings,
+            }), nil
+        case "deepgram":
+            if translate {
+                return nil, fmt.Errorf("Deepgram backend does not support --task translate")
+            }
+            if dgAPIKey == "" {
+                return nil, fmt.Errorf("Deepgram backend requires --deepgram-api-key or DEEPGRAM_API_KEY")
+            }
+            if model != "" {
+                dgModel = model
+            }
+            return transcribe.NewDeepgramBackend(transcribe.DeepgramOptions{
+                APIKey:      dgAPIKey,
+                BaseURL:     dgBaseURL,
+                Model:       dgModel,
+                Language:    dgLanguage,
+                Diarize:     dgDiarize,
+                SmartFormat: dgSmartFormat,
+                Utterances:  dgUtterances,
+                Retry:       retry,
+            }), nil
+        case "assemblyai":
+            if translate {
+                return nil, fmt.Errorf("AssemblyAI backend does not support --task translate")
+            }
+            if aaiAPIKey == "" {
+                return nil, fmt.Errorf("AssemblyAI backend requires --assemblyai-api-key or ASSEMBLYAI_API_KEY")
+            }
+            if model != "" {
+                aaiModel = model
+            }
+            async := transcribe.DefaultAsyncOptions
+            async.PollInterval = pollEvery
+            return transcribe.NewAssemblyAIBackend(transcribe.AssemblyAIOptions{
+                APIKey:        aaiAPIKey,
+                BaseURL:       aaiBaseURL,
+                SpeechModel:   aaiModel,
+                Language:      aaiLanguage,
+                SpeakerLabels: aaiSpeakers,
+                Retry:         retry,
+            }, async), nil
+        case "whispercpp":
+            if translate {
+                return nil, fmt.Errorf("whispercpp backend does not support --task translate")
+            }
+            if model != "" {
+                wcppModel = model
+            }
+            if wcppModel == "" {
+                return nil, fmt.Errorf("whispercpp backend requires --whispercpp-model (path to a GGML model)")
+            }
+            return transcribe.NewWhisperCppBackend(wcppBin, wcppModel, wcppThreads, wcppLanguage), nil
+        default:
+            // Anything else is an external mrp-backend-<name> executable.
+            path, ok := transcribe.FindPlugin(name)
+            if !ok {
+                return nil, fmt.Errorf("unknown backend: %s", name)
+            }
+            opts := map[string]string{}
+            for k, v := range pluginOpts {
+                opts[k] = v
+            }
+            if model != "" {
+                opts["model"] = model
+            }
+            if translate {
+                opts["task"] = task
+            }
+            if _, set := opts["glossary"]; !set && len(terms) > 0 {
+                opts["glossary"] = strings.Join(terms, "\n")
+            }
+            return transcribe.NewPluginBackend(name, path, opts), nil
+        }
+    }
+
+    if single {
+        be, err = buildBackend(names[0])
+        if err == nil && names[0] == "local" {
+            err = setupLocal()
         }
-        // Respect env default for device if user did not choose
-        if strings.ToLower(localDevice) == "auto" {
-            if envDev := strings.ToLower(strings.TrimSpace(os.Getenv("MRP_DEFAULT_LOCAL_DEVICE"))); envDev == "cpu" || envDev == "cuda" {
-                localDevice = envDev
-            } else if envDev2 := strings.ToLower(strings.TrimSpace(os.Getenv("MRP_LOCAL_DEVICE"))); envDev2 == "cpu" || envDev2 == "cuda" {
-                localDevice = envDev2
+        if err != nil {
+            fail("%v", err)
+            os.Exit(1)
+        }
+    } else {
+        var members []transcribe.NamedBackend
+        for _, n := range names {
+            b, err := buildBackend(n)
+            if err != nil {
+                warn("leaving %s out of the fallback chain: %v", n, err)
+                continue
             }
+            m := transcribe.NamedBackend{Name: n, Backend: b}
+            if n == "local" {
+                m.Setup = setupLocal
+            }
+            members = append(members, m)
         }
-        if py, err := ensureLocalFasterWhisper(ctx); err != nil {
-            fail("local backend setup failed: %v", err)
+        if len(members) == 0 {
+            fail("no usable backend in %s", backend)
             os.Exit(1)
-        } else if py != "" {
-            os.Setenv("MRP_PY", py)
         }
-        be = transcribe.NewFasterWhisperBackend(localModel, localDevice)
-    default:
-        fail("unknown backend: %s", backend)
-        os.Exit(2)
+        be = transcribe.NewFallbackBackend(members, func(name string, err error) {
+            warn("%s backend failed (%v); falling back to the next backend", name, err)
+        })
+    }
+
+    // Cache transcripts by audio content + backend settings so re-runs that only change
+    // metadata or diarization skip the transcription.
+    if !noCache {
+        if cacheDir == "" {
+            if cacheDir, err = cache.DefaultDir(); err != nil {
+                warn("transcript cache disabled: %v", err)
+            }
+        }
+        if cacheDir != "" {
+            settings := backend
+            if fp, isFP := be.(transcribe.Fingerprinter); isFP {
+                settings = fp.Fingerprint()
+            }
+            be = cache.NewBackend(be, cache.Store{Dir: cacheDir}, settings, refreshCache, func(key string) {
+                ok("Using cached transcript %s (pass --refresh to redo)", shortKey(key))
+            })
+        }
+    }
+
+    // Backends holding resources (e.g. the faster-whisper worker) are shut down at the end.
+    if c, isCloser := be.(io.Closer); isCloser {
+        defer c.Close()
     }
 
-    // Step 3: transcribe
-    info("Transcribing using %s backend...", backend)
-    tr, err := be.Transcribe(ctx, audioPath)
+    ch, err := parseChannel(channel)
     if err != nil {
-        fail("transcription failed: %v", err)
-        os.Exit(1)
+        fail("%v", err)
+        os.Exit(2)
     }
-    ok("Transcription done: %d segments", len(tr.Segments))
+    if perTrack != "" && perTrack != "streams" && perTrack != "channels" {
+        fail("invalid --per-track %q (want streams or channels)", perTrack)
+        os.Exit(2)
+    }
+    if perTrack == "channels" && ch != media.AllChannels {
+        fail("--channel cannot be combined with --per-track channels")
+        os.Exit(2)
+    }
+    if perTrack == "streams" && audioStream >= 0 {
+        fail("--audio-stream cannot be combined with --per-track streams, which transcribes every stream")
+        os.Exit(2)
+    }
+    filters, err := media.PreprocessFilters(preprocess)
+    if err != nil {
+        fail("%v", err)
+        os.Exit(2)
+    }
+    if silenceDB > 0 {
+        fail("--silence-db must be negative (dBFS), e.g. -45")
+        os.Exit(2)
+    }
+    startSec, err := parseClock(clipStart)
+    if err != nil {
+        fail("invalid --start: %v", err)
+        os.Exit(2)
+    }
+    endSec, err := parseClock(clipEnd)
+    if err != nil {
+        fail("invalid --end: %v", err)
+        os.Exit(2)
+    }
+    if endSec > 0 && endSec <= startSec {
+        fail("--end must be after --start")
+        os.Exit(2)
+    }
+    var trimOpts *media.TrimOptions
+    if trimSilence || maxSilence > 0 {
+        trimOpts = &media.TrimOptions{Edges: trimSilence, MaxGapSec: maxSilence.Seconds(), ThresholdDB: silenceDB}
+    }
+    trackOpts := trackOptions{stream: audioStream, channel: ch, perTrack: perTrack, names: trackNames}
 
-    // Step 4: diarization (minimal option)
     var diarizerImpl diarize.Diarizer
     switch strings.ToLower(diarizer) {
     case "none":
@@ -216,33 +655,309 @@ func main() {
         fail("unknown diarization mode: %s", diarizer)
         os.Exit(2)
     }
-    info("Applying diarization: %s...", diarizer)
-    if err := diarizerImpl.AssignSpeakers(ctx, &tr); err != nil {
+
+    opts := runOptions{
+        extract:  media.ExtractOptions{TmpDir: tmpDir, Filters: filters, StartSec: startSec, EndSec: endSec},
+        backend:  backend,
+        diarizer: diarizer,
+        diarize:  diarizerImpl,
+        fixes:    dict,
+        tracks:   trackOpts,
+        trim:     trimOpts,
+        render:   output.RenderOptions{WordTimings: wordTimings},
+        modelFor: func(name string) string { if model != "" { return model }; return modelFromBackend(name, openaiModel, cfModel, localModel, wcppModel, dgModel, aaiModel) },
+        meta: output.Metadata{
+            Title:     eventTitle,
+            Desc:      eventDesc,
+            Attendees: []string(attendees),
+            Backend:   backend,
+        },
+    }
+    // record the exact filters, not just the preset name, so the run can be reproduced
+    if len(filters) > 0 {
+        opts.meta.Preprocess = strings.Join(filters, ",")
+        if _, isPreset := media.Presets[strings.ToLower(preprocess)]; isPreset {
+            opts.meta.Preprocess = strings.ToLower(preprocess) + ": " + opts.meta.Preprocess
+        }
+    }
+    if startSec > 0 || endSec > 0 {
+        until := "end"
+        if endSec > 0 {
+            until = secToClock(endSec)
+        }
+        opts.meta.Clip = secToClock(startSec) + " – " + until
+    }
+    if single {
+        opts.meta.Model = opts.modelFor(names[0])
+    }
+
+    failed := 0
+    for i, in := range inputs {
+        out := outPath
+        if out == "" {
+            base := strings.TrimSuffix(filepath.Base(in), filepath.Ext(in))
+            out = base + ".md"
+        }
+        if len(inputs) > 1 {
+            info("[%d/%d] %s", i+1, len(inputs), in)
+        }
+        fileCtx, fileCancel := context.WithTimeout(context.Background(), 2*time.Hour)
+        err := processInput(fileCtx, be, opts, in, out)
+        fileCancel()
+        if err != nil {
+            fail("%s: %v", in, err)
+            errorHint(err)
+            failed++
+        }
+    }
+    if failed > 0 {
+        if c, isCloser := be.(io.Closer); isCloser {
+            c.Close()
+        }
+        if len(inputs) > 1 {
+            fail("%d of %d inputs failed", failed, len(inputs))
+        }
+        os.Exit(1)
+    }
+}
+
+// runOptions carries the settings shared by every input of a run.
+type runOptions struct {
+    extract  media.ExtractOptions // tmp dir and preprocessing; stream and channel are per track
+    backend  string
+    diarizer string
+    diarize  diarize.Diarizer
+    fixes    *correct.Dictionary // nil when --corrections is not given
+    tracks   trackOptions
+    trim     *media.TrimOptions // nil unless --trim-silence or --max-silence
+    render   output.RenderOptions
+    modelFor func(backend string) string
+    meta     output.Metadata // Source and Generated are filled in per input
+}
+
+// processInput runs extraction, transcription, diarization and rendering for one recording.
+func processInput(ctx context.Context, be transcribe.Backend, opts runOptions, inPath, outPath string) error {
+    // Step 1: inspect the file and pick the audio to transcribe
+    probe, err := media.Probe(ctx, inPath)
+    if err != nil {
+        // ffprobe ships with ffmpeg, but extraction can still work without it
+        warn("could not inspect %s: %v", inPath, err)
+    } else {
+        if len(probe.Audio) == 0 {
+            return fmt.Errorf("%s: %w", inPath, media.ErrNoAudio)
+        }
+        video := "no video"
+        if len(probe.Video) > 0 {
+            v := probe.Video[0]
+            video = fmt.Sprintf("%s %dx%d", v.Codec, v.Width, v.Height)
+        }
+        ok("Media: %s, %s, %s, %d audio stream(s)", probe.Format, probe.Duration.Truncate(time.Second), video, len(probe.Audio))
+        if start := opts.extract.StartSec; probe.Duration > 0 && start >= probe.Duration.Seconds() {
+            return fmt.Errorf("--start %s is past the end of the recording (%s)", secToClock(start), probe.Duration.Truncate(time.Second))
+        }
+    }
+    tracks, err := selectTracks(probe, opts.tracks, opts.extract)
+    if err != nil {
+        return err
+    }
+
+    // Step 2: extract and transcribe each track. Temporary audio goes as soon as this
+    // input is done, so a long batch does not fill the disk.
+    var temps []string
+    defer func() {
+        for _, p := range temps {
+            os.Remove(p)
+        }
+    }()
+    parts := make([]transcribe.Transcript, 0, len(tracks))
+    labels := make([]string, 0, len(tracks))
+    for _, t := range tracks {
+        what := "audio"
+        if t.label != "" {
+            what = "track " + t.label
+        }
+        if probe != nil {
+            for _, a := range probe.Audio {
+                if a.Index == t.extract.Stream {
+                    what += fmt.Sprintf(" from stream #%d (%s)", a.Index, describeAudio(a))
+                }
+            }
+        }
+        if t.extract.Channel >= 0 {
+            what += fmt.Sprintf(", channel %d", t.extract.Channel)
+        }
+        info("Extracting %s via ffmpeg...", what)
+        audioPath, err := media.ExtractAudio(ctx, inPath, t.extract)
+        if err != nil {
+            return fmt.Errorf("audio extraction failed: %w", err)
+        }
+        temps = append(temps, audioPath)
+        ok("Audio ready: %s", audioPath)
+        clipped := t.extract.StartSec > 0 || t.extract.EndSec > 0
+        var fullSec float64 // length of the extracted audio, before trimming
+        if clipped || opts.trim != nil {
+            wi, err := media.ReadWAVInfo(audioPath)
+            if err != nil {
+                return fmt.Errorf("reading extracted audio: %w", err)
+            }
+            fullSec = wi.DurationSec()
+        }
+
+        // Dead air is cut before transcription; timeMap puts the timestamps back.
+        var timeMap media.TimeMap
+        if opts.trim != nil {
+            trimmed, tm, err := media.TrimSilence(audioPath, opts.extract.TmpDir, *opts.trim)
+            if err != nil {
+                return fmt.Errorf("silence trimming failed: %w", err)
+            }
+            if tm == nil {
+                info("No silence to trim")
+            } else {
+                temps = append(temps, trimmed)
+                ok("Skipping %s of silence in %d piece(s)", secToClock(tm.RemovedSec(fullSec)), len(tm))
+            }
+            audioPath, timeMap = trimmed, tm
+        }
+
+        info("Transcribing using %s backend...", opts.backend)
+        var bar *progressBar
+        if pr, isReporter := be.(transcribe.ProgressReporter); isReporter {
+            bar = newProgressBar()
+            pr.OnProgress(bar.update)
+        }
+        tr, err := be.Transcribe(ctx, audioPath)
+        if bar != nil {
+            bar.done()
+        }
+        if err != nil {
+            return fmt.Errorf("transcription failed: %w", err)
+        }
+        ok("Transcription done: %d segments", len(tr.Segments))
+        if timeMap != nil {
+            transcribe.MapTimes(&tr, timeMap.Source)
+        }
+        if timeMap != nil || (clipped && tr.Duration == 0) {
+            // the backend measured the trimmed audio, or nothing; report what was extracted
+            tr.Duration = time.Duration(fullSec * float64(time.Second))
+        }
+        if start := t.extract.StartSec; start > 0 {
+            // back to the position in the full recording, so links and subtitles line up
+            transcribe.MapTimes(&tr, func(s, e float64) (float64, float64) { return s + start, e + start })
+        }
+        parts = append(parts, tr)
+        labels = append(labels, t.label)
+    }
+    tr := parts[0]
+    if len(parts) > 1 {
+        // speakers come from the tracks, so diarization only fills in what is left
+        tr = transcribe.MergeTracks(parts, labels)
+        ok("Merged %d tracks: %d segments", len(parts), len(tr.Segments))
+    }
+
+    // Step 2b: dictionary corrections, logged one by one for auditing
+    if !opts.fixes.Empty() {
+        subs := opts.fixes.Apply(&tr)
+        for _, s := range subs {
+            info("  [%s] %s", secToClock(s.At), s)
+        }
+        ok("Corrections applied: %d", len(subs))
+    }
+
+    // Step 3: diarization (minimal option)
+    info("Applying diarization: %s...", opts.diarizer)
+    if err := opts.diarize.AssignSpeakers(ctx, &tr); err != nil {
         warn("diarization skipped/failed: %v", err)
     } else {
         ok("Diarization applied")
     }
 
-    // Step 5: render markdown
-    meta := output.Metadata{
-        Title:     eventTitle,
-        Desc:      eventDesc,
-        Attendees: []string(attendees),
-        Source:    inPath,
-        Backend:   backend,
-        Model:     func() string { if model != "" { return model }; return modelFromBackend(backend, openaiModel, cfModel, localModel) }(),
-        Generated: time.Now().Format(time.RFC3339),
+    // Step 4: render markdown
+    meta := opts.meta
+    if tr.Backend != "" {
+        // a fallback chain reports which member actually did the work
+        meta.Backend = tr.Backend
+        meta.Model = opts.modelFor(tr.Backend)
+    }
+    meta.Source = inPath
+    meta.Generated = time.Now().Format(time.RFC3339)
+    if probe != nil {
+        // not every backend reports duration; the container always does
+        if tr.Duration == 0 {
+            tr.Duration = probe.Duration
+        }
+        if !probe.Created.IsZero() {
+            meta.Recorded = probe.Created.Local().Format(time.RFC3339)
+        }
     }
 
-    md := output.RenderMarkdown(meta, tr)
+    md := output.RenderMarkdown(meta, tr, opts.render)
     if err := os.WriteFile(outPath, []byte(md), 0o644); err != nil {
-        fail("writing output: %v", err)
-        os.Exit(1)
+        return fmt.Errorf("writing output: %w", err)
     }
     ok("Wrote %s", outPath)
+    return nil
+}
+
+// describeAudio summarises an audio stream for the log, e.g. "opus 48000 Hz stereo, eng".
+func describeAudio(a media.AudioStream) string {
+    layout := a.ChannelLayout
+    if layout == "" {
+        layout = fmt.Sprintf("%d ch", a.Channels)
+    }
+    s := fmt.Sprintf("%s %d Hz %s", a.Codec, a.SampleRate, layout)
+    if a.Language != "" && a.Language != "und" {
+        s += ", " + a.Language
+    }
+    return s
+}
+
+// parseClock parses a position in the recording given as HH:MM:SS, MM:SS or plain
+// seconds; the last field may have a fraction. Empty means 0.
+func parseClock(v string) (float64, error) {
+    v = strings.TrimSpace(v)
+    if v == "" {
+        return 0, nil
+    }
+    fields := strings.Split(v, ":")
+    if len(fields) > 3 {
+        return 0, fmt.Errorf("%q is not HH:MM:SS", v)
+    }
+    sec := 0.0
+    for i, f := range fields {
+        n, err := strconv.ParseFloat(f, 64)
+        if err != nil || n < 0 || (i > 0 && n >= 60) || (i < len(fields)-1 && n != math.Trunc(n)) {
+            return 0, fmt.Errorf("%q is not HH:MM:SS", v)
+        }
+        sec = sec*60 + n
+    }
+    return sec, nil
+}
+
+// envOr returns the trimmed value of env var key, or def when it is unset or blank.
+func envOr(key, def string) string {
+    if v := strings.TrimSpace(os.Getenv(key)); v != "" {
+        return v
+    }
+    return def
+}
+
+// errorHint prints advice matching the kind of a transcription failure, if any.
+func errorHint(err error) {
+    switch {
+    case errors.Is(err, transcribe.ErrAuthFailed):
+        warn("the backend rejected the credentials; check the API key/token")
+    case errors.Is(err, transcribe.ErrRateLimited):
+        warn("rate limited or out of quota; wait and retry, or raise --retry-max")
+    case errors.Is(err, transcribe.ErrPayloadTooLarge):
+        warn("audio upload too large; lower --chunk-max")
+    case errors.Is(err, transcribe.ErrUnavailable):
+        warn("the backend is unavailable; try again later or pick another --backend")
+    }
 }
 
-func modelFromBackend(backend, openaiModel, cfModel, localModel string) string {
+var knownBackends = map[string]bool{"openai": true, "cloudflare": true, "local": true, "whispercpp": true, "deepgram": true, "assemblyai": true}
+
+func modelFromBackend(backend, openaiModel, cfModel, localModel, wcppModel, dgModel, aaiModel string) string {
     switch backend {
     case "openai":
         return openaiModel
@@ -250,6 +965,12 @@ func modelFromBackend(backend, openaiModel, cfModel, localModel string) string {
         return cfModel
     case "local":
         return localModel
+    case "whispercpp":
+        return filepath.Base(wcppModel)
+    case "deepgram":
+        return dgModel
+    case "assemblyai":
+        return aaiModel
     default:
         return ""
     }
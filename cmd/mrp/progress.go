@@ -0,0 +1,69 @@
+package main
+
+import (
+    "fmt"
+    "os"
+    "strings"
+    "time"
+
+    "github.com/zudsniper/meet-recording-processor/internal/transcribe"
+)
+
+const progressWidth = 30
+
+// progressBar renders transcription progress on stderr. On a terminal it redraws a
+// single line; otherwise it logs a line every 10% so redirected logs stay readable.
+type progressBar struct {
+    tty      bool
+    lastStep int
+    drawn    bool
+}
+
+func newProgressBar() *progressBar {
+    tty := false
+    if fi, err := os.Stderr.Stat(); err == nil {
+        tty = fi.Mode()&os.ModeCharDevice != 0
+    }
+    return &progressBar{tty: tty, lastStep: -1}
+}
+
+func (p *progressBar) update(pr transcribe.Progress) {
+    frac := pr.Fraction()
+    if pr.TotalSec <= 0 {
+        return
+    }
+    pct := int(frac * 100)
+    eta := "--"
+    if d := pr.ETA(); d > 0 {
+        eta = d.Round(time.Second).String()
+    }
+    if !p.tty {
+        if step := pct / 10; step > p.lastStep {
+            p.lastStep = step
+            info("Transcribed %d%% (%s/%s, ETA %s)", pct, secToClock(pr.ProcessedSec), secToClock(pr.TotalSec), eta)
+        }
+        return
+    }
+    filled := int(frac * progressWidth)
+    bar := strings.Repeat("#", filled) + strings.Repeat("-", progressWidth-filled)
+    fmt.Fprintf(os.Stderr, "\r\033[K"+colorBlue+"[info] "+colorReset+"[%s] %3d%% %s/%s ETA %s", bar, pct, secToClock(pr.ProcessedSec), secToClock(pr.TotalSec), eta)
+    p.drawn = true
+}
+
+// done ends the progress line so following log output starts on a fresh line.
+func (p *progressBar) done() {
+    if p.tty && p.drawn {
+        fmt.Fprintln(os.Stderr)
+    }
+    p.drawn = false
+    p.lastStep = -1
+}
+
+func secToClock(sec float64) string {
+    d := time.Duration(sec) * time.Second
+    h := int(d.Hours())
+    m := int(d.Minutes()) % 60
+    s := int(d.Seconds()) % 60
+    if h > 0 { return fmt.Sprintf("%d:%02d:%02d", h, m, s) }
+    return fmt.Sprintf("%02d:%02d", m, s)
+}
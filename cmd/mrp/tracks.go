@@ -0,0 +1,124 @@
+package main
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+
+    "github.com/zudsniper/meet-recording-processor/internal/media"
+)
+
+// trackOptions is the user's choice of audio: which stream, which channel, and whether
+// to transcribe every stream or channel on its own.
+type trackOptions struct {
+    stream   int      // --audio-stream: position among the audio streams, -1 for the default track
+    channel  int      // --channel, or media.AllChannels
+    perTrack string   // --per-track: "", "streams" or "channels"
+    names    []string // --track-names: speaker labels for per-track mode, in track order
+}
+
+// track is one piece of audio to extract and transcribe. label is the speaker name for
+// per-track mode and empty otherwise.
+type track struct {
+    label   string
+    extract media.ExtractOptions
+}
+
+// parseChannel accepts left, right or a 0-based channel number; empty means all channels.
+func parseChannel(v string) (int, error) {
+    switch strings.ToLower(strings.TrimSpace(v)) {
+    case "", "all", "mix":
+        return media.AllChannels, nil
+    case "left", "l":
+        return 0, nil
+    case "right", "r":
+        return 1, nil
+    }
+    n, err := strconv.Atoi(v)
+    if err != nil || n < 0 {
+        return 0, fmt.Errorf("invalid --channel %q (want left, right or a channel number from 0)", v)
+    }
+    return n, nil
+}
+
+// selectTracks resolves the options against what ffprobe found. probe may be nil when
+// ffprobe is unavailable, in which case only whole-file extraction is possible. base
+// carries the extraction settings shared by every track; Stream and Channel are set here.
+func selectTracks(probe *media.Info, o trackOptions, base media.ExtractOptions) ([]track, error) {
+    extract := func(stream, channel int) media.ExtractOptions {
+        e := base
+        e.Stream, e.Channel = stream, channel
+        return e
+    }
+    if probe == nil {
+        if o.stream >= 0 || o.perTrack != "" {
+            return nil, fmt.Errorf("--audio-stream and --per-track need ffprobe to list the audio streams")
+        }
+        return []track{{extract: extract(media.AnyStream, o.channel)}}, nil
+    }
+
+    a, _ := probe.DefaultAudio()
+    if o.stream >= 0 {
+        if o.stream >= len(probe.Audio) {
+            return nil, fmt.Errorf("--audio-stream %d out of range: the file has %d audio stream(s)", o.stream, len(probe.Audio))
+        }
+        a = probe.Audio[o.stream]
+    }
+    checkChannel := func(s media.AudioStream) error {
+        if o.channel >= 0 && s.Channels > 0 && o.channel >= s.Channels {
+            return fmt.Errorf("--channel %d out of range: audio stream #%d has %d channel(s)", o.channel, s.Index, s.Channels)
+        }
+        return nil
+    }
+
+    label := func(i int, fallback string) string {
+        if i < len(o.names) && o.names[i] != "" {
+            return o.names[i]
+        }
+        return fallback
+    }
+    switch o.perTrack {
+    case "":
+        if err := checkChannel(a); err != nil {
+            return nil, err
+        }
+        return []track{{extract: extract(a.Index, o.channel)}}, nil
+    case "streams":
+        if len(probe.Audio) < 2 {
+            return nil, fmt.Errorf("--per-track streams: the file has only one audio stream (try --per-track channels)")
+        }
+        tracks := make([]track, 0, len(probe.Audio))
+        for i, s := range probe.Audio {
+            if err := checkChannel(s); err != nil {
+                return nil, err
+            }
+            name := s.Title
+            if name == "" {
+                name = fmt.Sprintf("Track %d", i+1)
+            }
+            tracks = append(tracks, track{
+                label:   label(i, name),
+                extract: extract(s.Index, o.channel),
+            })
+        }
+        return tracks, nil
+    case "channels":
+        if a.Channels < 2 {
+            return nil, fmt.Errorf("--per-track channels: audio stream #%d is mono", a.Index)
+        }
+        tracks := make([]track, 0, a.Channels)
+        for c := 0; c < a.Channels; c++ {
+            name := fmt.Sprintf("Channel %d", c+1)
+            if a.Channels == 2 {
+                name = []string{"Left", "Right"}[c]
+            }
+            tracks = append(tracks, track{
+                label:   label(c, name),
+                extract: extract(a.Index, c),
+            })
+        }
+        return tracks, nil
+    default:
+        return nil, fmt.Errorf("invalid --per-track %q (want streams or channels)", o.perTrack)
+    }
+}
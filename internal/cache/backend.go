@@ -0,0 +1,71 @@
+package cache
+
+import (
+    "context"
+    "fmt"
+    "path/filepath"
+    "time"
+
+    "github.com/zudsniper/meet-recording-processor/internal/transcribe"
+)
+
+// cachedBackend serves transcripts from a Store and fills it on misses.
+type cachedBackend struct {
+    inner    transcribe.Backend
+    store    Store
+    settings string
+    refresh  bool             // skip lookups but still store fresh results
+    onHit    func(key string) // optional; called whenever a cached transcript is used
+}
+
+// NewBackend wraps inner with a transcript cache. settings must capture everything that
+// changes the output (backend name, model, decoding options); it is hashed together with
+// the audio content to form the key. With refresh set, existing entries are overwritten.
+func NewBackend(inner transcribe.Backend, store Store, settings string, refresh bool, onHit func(key string)) transcribe.Backend {
+    return &cachedBackend{inner: inner, store: store, settings: settings, refresh: refresh, onHit: onHit}
+}
+
+func (c *cachedBackend) Transcribe(ctx context.Context, audioPath string) (transcribe.Transcript, error) {
+    sum, err := HashFile(audioPath)
+    if err != nil {
+        return transcribe.Transcript{}, fmt.Errorf("hash audio: %w", err)
+    }
+    key := Key(sum, c.settings)
+    if !c.refresh {
+        if e, found := c.store.Get(key); found {
+            if c.onHit != nil {
+                c.onHit(key)
+            }
+            return e.Transcript, nil
+        }
+    }
+    tr, err := c.inner.Transcribe(ctx, audioPath)
+    if err != nil {
+        return tr, err
+    }
+    // A failed write only costs a future re-transcription, so it is not fatal.
+    _ = c.store.Put(Entry{
+        Key:         key,
+        Created:     time.Now(),
+        AudioSHA256: sum,
+        Audio:       filepath.Base(audioPath),
+        Settings:    c.settings,
+        Transcript:  tr,
+    })
+    return tr, nil
+}
+
+// OnProgress forwards to the wrapped backend when it reports progress.
+func (c *cachedBackend) OnProgress(fn transcribe.ProgressFunc) {
+    if pr, ok := c.inner.(transcribe.ProgressReporter); ok {
+        pr.OnProgress(fn)
+    }
+}
+
+// Close releases the wrapped backend's resources, if it holds any.
+func (c *cachedBackend) Close() error {
+    if cl, ok := c.inner.(interface{ Close() error }); ok {
+        return cl.Close()
+    }
+    return nil
+}
@@ -0,0 +1,187 @@
+package cache
+
+import (
+    "crypto/sha256"
+    "encoding/hex"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io"
+    "os"
+    "path/filepath"
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/zudsniper/meet-recording-processor/internal/transcribe"
+)
+
+// Store is a directory of cached transcripts, one JSON file per key.
+type Store struct {
+    Dir string
+}
+
+// Entry is what gets written for each cached transcript.
+type Entry struct {
+    Key         string                `json:"key"`
+    Created     time.Time             `json:"created"`
+    AudioSHA256 string                `json:"audio_sha256"`
+    Audio       string                `json:"audio"` // base name of the audio file, for listings
+    Settings    string                `json:"settings"`
+    Transcript  transcribe.Transcript `json:"transcript"`
+}
+
+// Info describes a cache file on disk, as returned by List.
+type Info struct {
+    Entry
+    Path     string
+    Size     int64
+    LastUsed time.Time // file mtime; bumped on every hit
+}
+
+// DefaultDir returns $MRP_CACHE_DIR or ~/.mrp/cache.
+func DefaultDir() (string, error) {
+    if d := strings.TrimSpace(os.Getenv("MRP_CACHE_DIR")); d != "" {
+        return d, nil
+    }
+    home, err := os.UserHomeDir()
+    if err != nil {
+        return "", fmt.Errorf("home dir: %w", err)
+    }
+    return filepath.Join(home, ".mrp", "cache"), nil
+}
+
+// HashFile returns the hex SHA-256 of a file's contents.
+func HashFile(path string) (string, error) {
+    f, err := os.Open(path)
+    if err != nil {
+        return "", err
+    }
+    defer f.Close()
+    h := sha256.New()
+    if _, err := io.Copy(h, f); err != nil {
+        return "", err
+    }
+    return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Key combines the audio hash with the settings that influence the transcript.
+func Key(audioSHA, settings string) string {
+    h := sha256.Sum256([]byte(audioSHA + "\n" + settings))
+    return hex.EncodeToString(h[:])
+}
+
+// validKey reports whether k looks like a key made by Key: 64 lower-case hex digits.
+func validKey(k string) bool {
+    if len(k) != 2*sha256.Size {
+        return false
+    }
+    _, err := hex.DecodeString(k)
+    return err == nil && strings.ToLower(k) == k
+}
+
+func (s Store) path(key string) string { return filepath.Join(s.Dir, key+".json") }
+
+// Get returns the cached entry for key, if present, and marks it as recently used.
+func (s Store) Get(key string) (Entry, bool) {
+    p := s.path(key)
+    b, err := os.ReadFile(p)
+    if err != nil {
+        return Entry{}, false
+    }
+    var e Entry
+    if err := json.Unmarshal(b, &e); err != nil || e.Key != key {
+        return Entry{}, false
+    }
+    now := time.Now()
+    os.Chtimes(p, now, now)
+    return e, true
+}
+
+// Put writes e atomically so a concurrent reader never sees a partial file.
+func (s Store) Put(e Entry) error {
+    if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+        return err
+    }
+    b, err := json.Marshal(e)
+    if err != nil {
+        return err
+    }
+    tmp, err := os.CreateTemp(s.Dir, ".tmp-*")
+    if err != nil {
+        return err
+    }
+    if _, err := tmp.Write(b); err != nil {
+        tmp.Close()
+        os.Remove(tmp.Name())
+        return err
+    }
+    if err := tmp.Close(); err != nil {
+        os.Remove(tmp.Name())
+        return err
+    }
+    return os.Rename(tmp.Name(), s.path(e.Key))
+}
+
+// List returns all entries, most recently used first. Unreadable files, and stray JSON
+// files that are not entries stored under their own key, are skipped.
+func (s Store) List() ([]Info, error) {
+    des, err := os.ReadDir(s.Dir)
+    if errors.Is(err, os.ErrNotExist) {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+    var out []Info
+    for _, de := range des {
+        if de.IsDir() || !strings.HasSuffix(de.Name(), ".json") {
+            continue
+        }
+        p := filepath.Join(s.Dir, de.Name())
+        fi, err := de.Info()
+        if err != nil {
+            continue
+        }
+        b, err := os.ReadFile(p)
+        if err != nil {
+            continue
+        }
+        var e Entry
+        if json.Unmarshal(b, &e) != nil || !validKey(e.Key) || de.Name() != e.Key+".json" {
+            continue
+        }
+        out = append(out, Info{Entry: e, Path: p, Size: fi.Size(), LastUsed: fi.ModTime()})
+    }
+    sort.Slice(out, func(i, j int) bool { return out[i].LastUsed.After(out[j].LastUsed) })
+    return out, nil
+}
+
+// Prune removes entries unused for longer than maxAge (0 disables), then evicts the
+// least recently used entries until the total size is at most maxBytes (0 disables).
+func (s Store) Prune(maxAge time.Duration, maxBytes int64) (removed []Info, err error) {
+    infos, err := s.List()
+    if err != nil {
+        return nil, err
+    }
+    var total int64
+    for _, in := range infos {
+        total += in.Size
+    }
+    cutoff := time.Now().Add(-maxAge)
+    // oldest first
+    for i := len(infos) - 1; i >= 0; i-- {
+        in := infos[i]
+        tooOld := maxAge > 0 && in.LastUsed.Before(cutoff)
+        tooBig := maxBytes > 0 && total > maxBytes
+        if !tooOld && !tooBig {
+            continue
+        }
+        if err := os.Remove(in.Path); err != nil {
+            return removed, err
+        }
+        total -= in.Size
+        removed = append(removed, in)
+    }
+    return removed, nil
+}
@@ -0,0 +1,356 @@
+package correct
+
+import (
+    "bufio"
+    "fmt"
+    "os"
+    "regexp"
+    "strings"
+    "unicode"
+
+    "github.com/zudsniper/meet-recording-processor/internal/transcribe"
+)
+
+// Kinds of substitution, from most to least certain.
+const (
+    KindRule     = "rule"     // explicit "wrong => right" entry
+    KindCase     = "case"     // term matched exactly apart from letter case
+    KindFuzzy    = "fuzzy"    // term within a small edit distance
+    KindPhonetic = "phonetic" // term sounds the same (same consonant skeleton)
+)
+
+// Substitution records one change made to a transcript, for auditing.
+type Substitution struct {
+    Segment int     // index into Transcript.Segments
+    At      float64 // segment start, seconds
+    From    string
+    To      string
+    Kind    string
+}
+
+func (s Substitution) String() string {
+    return fmt.Sprintf("%q → %q (%s)", s.From, s.To, s.Kind)
+}
+
+type rule struct {
+    from []string // lower-cased words
+    to   string
+}
+
+type term struct {
+    text  string
+    words int
+    norm  string // lower-cased letters and digits only
+    key   string // phonetic key of norm
+}
+
+// Dictionary holds explicit replacements and canonical terms to fuzzy-match against.
+type Dictionary struct {
+    rules []rule
+    terms []term
+}
+
+// Load reads a dictionary file. Each non-blank line that is not a # comment is either
+// "wrong words => Right" (an explicit replacement) or a bare canonical term such as
+// "Kubernetes" that near-misses are corrected to.
+func Load(path string) (*Dictionary, error) {
+    f, err := os.Open(path)
+    if err != nil {
+        return nil, err
+    }
+    defer f.Close()
+    d := &Dictionary{}
+    sc := bufio.NewScanner(f)
+    n := 0
+    for sc.Scan() {
+        n++
+        line := strings.TrimSpace(sc.Text())
+        if line == "" || strings.HasPrefix(line, "#") {
+            continue
+        }
+        from, to, isRule := strings.Cut(line, "=>")
+        if !isRule {
+            d.AddTerm(line)
+            continue
+        }
+        from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+        if from == "" || to == "" {
+            return nil, fmt.Errorf("%s:%d: expected 'wrong => right'", path, n)
+        }
+        d.AddRule(from, to)
+    }
+    if err := sc.Err(); err != nil {
+        return nil, err
+    }
+    return d, nil
+}
+
+// AddRule replaces the word sequence from (matched case-insensitively) with to.
+func (d *Dictionary) AddRule(from, to string) {
+    var words []string
+    for _, w := range tokenRE.FindAllString(from, -1) {
+        words = append(words, strings.ToLower(w))
+    }
+    if len(words) > 0 {
+        d.rules = append(d.rules, rule{from: words, to: to})
+    }
+}
+
+// AddTerm adds a canonical spelling that case slips, typos and sound-alikes are corrected to.
+func (d *Dictionary) AddTerm(t string) {
+    t = strings.TrimSpace(t)
+    norm := normalize(t)
+    if norm == "" {
+        return
+    }
+    d.terms = append(d.terms, term{text: t, words: len(tokenRE.FindAllString(t, -1)), norm: norm, key: phoneticKey(norm)})
+}
+
+// Empty reports whether the dictionary has nothing to apply.
+func (d *Dictionary) Empty() bool { return d == nil || len(d.rules)+len(d.terms) == 0 }
+
+// Apply corrects every segment's text, and its word timings when present, in place and
+// returns the substitutions made. Matching is deterministic: explicit rules first, then
+// exact terms, then fuzzy and phonetic matches, always at whole-word boundaries and
+// preferring longer matches.
+func (d *Dictionary) Apply(tr *transcribe.Transcript) []Substitution {
+    if d.Empty() {
+        return nil
+    }
+    var subs []Substitution
+    for i := range tr.Segments {
+        seg := &tr.Segments[i]
+        text, made := d.correctText(seg.Text)
+        for _, s := range made {
+            s.Segment = i
+            s.At = seg.StartSec
+            subs = append(subs, s)
+        }
+        seg.Text = text
+        if len(made) > 0 && len(seg.Words) > 0 {
+            seg.Words = d.correctWords(seg.Words)
+        }
+    }
+    return subs
+}
+
+// tokenRE matches a word, keeping internal apostrophes and hyphens (don't, real-time).
+var tokenRE = regexp.MustCompile(`[\p{L}\p{N}]+(?:['’-][\p{L}\p{N}]+)*`)
+
+// maxWindow bounds how many words a single match may span.
+const maxWindow = 4
+
+// change is a substitution at byte offsets [start, end) of the text it was found in.
+type change struct {
+    start, end int
+    sub        Substitution
+}
+
+func (d *Dictionary) changes(text string) []change {
+    locs := tokenRE.FindAllStringIndex(text, -1)
+    var out []change
+    for i := 0; i < len(locs); {
+        n, to, kind := d.matchAt(text, locs, i)
+        if n == 0 {
+            i++
+            continue
+        }
+        start, end := locs[i][0], locs[i+n-1][1]
+        from := text[start:end]
+        to = matchCase(from, to)
+        if to != from {
+            out = append(out, change{start: start, end: end, sub: Substitution{From: from, To: to, Kind: kind}})
+        }
+        i += n
+    }
+    return out
+}
+
+func (d *Dictionary) correctText(text string) (string, []Substitution) {
+    var b strings.Builder
+    var subs []Substitution
+    last := 0
+    for _, c := range d.changes(text) {
+        b.WriteString(text[last:c.start])
+        b.WriteString(c.sub.To)
+        last = c.end
+        subs = append(subs, c.sub)
+    }
+    b.WriteString(text[last:])
+    return b.String(), subs
+}
+
+// correctWords applies the same corrections to a segment's word timings, so the words
+// keep spelling out the corrected text. A replacement spanning several words ("Cooper
+// Netties") becomes one word covering their combined time.
+func (d *Dictionary) correctWords(words []transcribe.Word) []transcribe.Word {
+    // matching runs on the words joined as the backend's text would be; pos[k] is where
+    // word k starts
+    var b strings.Builder
+    pos := make([]int, len(words))
+    for k, w := range words {
+        if k > 0 {
+            b.WriteByte(' ')
+        }
+        pos[k] = b.Len()
+        b.WriteString(w.Text)
+    }
+    text := b.String()
+    out := append([]transcribe.Word(nil), words...)
+    changes := d.changes(text)
+    // back to front, so earlier word indices stay valid as words are merged
+    for ci := len(changes) - 1; ci >= 0; ci-- {
+        c := changes[ci]
+        first, last := -1, -1
+        for k := range words {
+            end := pos[k] + len(words[k].Text)
+            if first < 0 && end > c.start {
+                first = k
+            }
+            if pos[k] < c.end {
+                last = k
+            }
+        }
+        if first < 0 || last < first {
+            continue
+        }
+        w := out[first]
+        w.Text = text[pos[first]:c.start] + c.sub.To + text[c.end:pos[last]+len(words[last].Text)]
+        w.End = out[last].End
+        for _, m := range out[first+1 : last+1] {
+            w.Probability = min(w.Probability, m.Probability)
+        }
+        out = append(append(out[:first], w), out[last+1:]...)
+    }
+    return out
+}
+
+// matchAt finds the best match starting at token i and returns how many tokens it spans
+// (0 for none), the replacement and the kind of match.
+func (d *Dictionary) matchAt(text string, locs [][]int, i int) (int, string, string) {
+    // the longest run of tokens from i separated only by spaces
+    run := 1
+    for run < maxWindow && i+run < len(locs) && strings.TrimSpace(text[locs[i+run-1][1]:locs[i+run][0]]) == "" {
+        run++
+    }
+    words := make([]string, run)
+    for k := 0; k < run; k++ {
+        words[k] = text[locs[i+k][0]:locs[i+k][1]]
+    }
+
+    best, bestTo := 0, ""
+    for _, r := range d.rules {
+        if len(r.from) > run || len(r.from) <= best {
+            continue
+        }
+        if equalFold(words[:len(r.from)], r.from) {
+            best, bestTo = len(r.from), r.to
+        }
+    }
+    if best > 0 {
+        return best, bestTo, KindRule
+    }
+
+    for n := run; n >= 1; n-- {
+        if t, ok := d.exactTerm(words[:n]); ok {
+            return n, t, KindCase
+        }
+    }
+
+    // closest fuzzy match over all window sizes; edit-distance matches beat phonetic ones
+    // and ties go to the longer window
+    bestN, bestKind, bestDist := 0, "", 0
+    for n := run; n >= 1; n-- {
+        cand := normalize(strings.Join(words[:n], ""))
+        for _, t := range d.terms {
+            if n < t.words-1 || n > t.words+2 {
+                continue
+            }
+            kind, dist := fuzzyKind(cand, n, t)
+            if kind == "" {
+                continue
+            }
+            if bestN == 0 || (kind == KindFuzzy && bestKind == KindPhonetic) || (kind == bestKind && dist < bestDist) {
+                bestN, bestTo, bestKind, bestDist = n, t.text, kind, dist
+            }
+        }
+    }
+    return bestN, bestTo, bestKind
+}
+
+func (d *Dictionary) exactTerm(words []string) (string, bool) {
+    joined := strings.Join(words, " ")
+    for _, t := range d.terms {
+        if strings.EqualFold(joined, t.text) {
+            return t.text, true
+        }
+    }
+    return "", false
+}
+
+// minSingleWord is the shortest term a single word is fuzzy matched against. Below it,
+// real words a letter away are too common ("stack" for Slack, "docket" for Docker);
+// shorter terms are still matched when misheard as several words ("dock er").
+const minSingleWord = 8
+
+// fuzzyKind decides whether cand, the normalised text of an n-word window, is a
+// misrecognition of t and returns the kind of match and the edit distance. A term split
+// into several words ("acme flow") is a distance-0 fuzzy match. Terms of four letters or
+// fewer are never fuzzy matched, and a single word only when the term is long and the
+// word is not a common English one. Phonetic matches must also start with the same
+// sound, since collapsing repeated classes would otherwise let a window swallow a
+// neighbouring word ("ask zud sniper").
+func fuzzyKind(cand string, n int, t term) (string, int) {
+    if len(t.norm) < 5 || cand == "" {
+        return "", 0
+    }
+    if n == 1 && (len(t.norm) < minSingleWord || isCommonWord(cand)) {
+        return "", 0
+    }
+    dist := levenshtein(cand, t.norm)
+    if dist <= len(t.norm)/5 {
+        return KindFuzzy, dist
+    }
+    longer := max(len(cand), len(t.norm))
+    if len(t.key) >= 3 && phoneticKey(cand) == t.key && dist <= longer/2 && sameOnset(cand, t.norm) {
+        return KindPhonetic, dist
+    }
+    return "", 0
+}
+
+func equalFold(a, b []string) bool {
+    for i := range a {
+        if !strings.EqualFold(a[i], b[i]) {
+            return false
+        }
+    }
+    return true
+}
+
+// matchCase adapts the replacement's case to the text it replaces: shouted text stays
+// upper case and a capitalised word stays capitalised, but replacements with their own
+// capitals (proper nouns, acronyms) are kept as written.
+func matchCase(from, to string) string {
+    letters, upper := 0, 0
+    for _, r := range from {
+        if unicode.IsLetter(r) {
+            letters++
+            if unicode.IsUpper(r) {
+                upper++
+            }
+        }
+    }
+    if letters > 1 && upper == letters {
+        return strings.ToUpper(to)
+    }
+    if strings.ToLower(to) != to {
+        return to
+    }
+    first := []rune(from)
+    if len(first) > 0 && unicode.IsUpper(first[0]) {
+        r := []rune(to)
+        r[0] = unicode.ToUpper(r[0])
+        return string(r)
+    }
+    return to
+}
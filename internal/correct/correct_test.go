@@ -0,0 +1,98 @@
+package correct
+
+import (
+    "strings"
+    "testing"
+
+    "github.com/zudsniper/meet-recording-processor/internal/transcribe"
+)
+
+func dict(entries ...string) *Dictionary {
+    d := &Dictionary{}
+    for _, e := range entries {
+        if from, to, isRule := strings.Cut(e, "=>"); isRule {
+            d.AddRule(strings.TrimSpace(from), strings.TrimSpace(to))
+        } else {
+            d.AddTerm(e)
+        }
+    }
+    return d
+}
+
+func TestApplyLeavesOrdinaryWords(t *testing.T) {
+    d := dict("Slack", "Sentry", "Docker", "Kubernetes")
+    for _, text := range []string{
+        "a stack of tools",
+        "post it in the black channel",
+        "the entry point",
+        "lock the locker",
+        "check the docket",
+        "we need a rocket",
+        "open a socket",
+    } {
+        tr := transcribe.Transcript{Segments: []transcribe.Segment{{Text: text}}}
+        if subs := d.Apply(&tr); len(subs) != 0 {
+            t.Errorf("%q: unexpected substitutions %v", text, subs)
+        }
+        if tr.Segments[0].Text != text {
+            t.Errorf("%q rewritten to %q", text, tr.Segments[0].Text)
+        }
+    }
+}
+
+func TestApplyCorrects(t *testing.T) {
+    d := dict("Kubernetes", "Zudsniper", "Docker", "jay son => JSON")
+    for _, c := range []struct {
+        in, want, kind string
+    }{
+        {"deploy it on cooper netties", "deploy it on Kubernetes", KindPhonetic},
+        {"the Kubernets cluster", "the Kubernetes cluster", KindFuzzy},
+        {"ask zud sniper about it", "ask Zudsniper about it", KindFuzzy},
+        {"run it in dock er", "run it in Docker", KindFuzzy},
+        {"DOCKER is fine", "DOCKER is fine", ""},
+        {"it returns jay son", "it returns JSON", KindRule},
+        {"we use docker", "we use Docker", KindCase},
+    } {
+        tr := transcribe.Transcript{Segments: []transcribe.Segment{{Text: c.in}}}
+        subs := d.Apply(&tr)
+        if got := tr.Segments[0].Text; got != c.want {
+            t.Errorf("%q: got %q, want %q", c.in, got, c.want)
+        }
+        if c.kind == "" {
+            if len(subs) != 0 {
+                t.Errorf("%q: unexpected substitutions %v", c.in, subs)
+            }
+        } else if len(subs) != 1 || subs[0].Kind != c.kind {
+            t.Errorf("%q: substitutions %v, want one %s", c.in, subs, c.kind)
+        }
+    }
+}
+
+func TestApplyCorrectsWords(t *testing.T) {
+    d := dict("Kubernetes")
+    tr := transcribe.Transcript{Segments: []transcribe.Segment{{
+        StartSec: 1, EndSec: 4,
+        Text: "on cooper netties, today",
+        Words: []transcribe.Word{
+            {Start: 1, End: 1.2, Text: "on", Probability: 0.9},
+            {Start: 1.2, End: 1.6, Text: "cooper", Probability: 0.8},
+            {Start: 1.6, End: 2.2, Text: "netties,", Probability: 0.6},
+            {Start: 2.5, End: 3, Text: "today", Probability: 0.9},
+        },
+    }}}
+    d.Apply(&tr)
+    words := tr.Segments[0].Words
+    want := []transcribe.Word{
+        {Start: 1, End: 1.2, Text: "on", Probability: 0.9},
+        {Start: 1.2, End: 2.2, Text: "Kubernetes,", Probability: 0.6},
+        {Start: 2.5, End: 3, Text: "today", Probability: 0.9},
+    }
+    if len(words) != len(want) {
+        t.Fatalf("got words %v, want %v", words, want)
+    }
+    for i := range want {
+        if words[i] != want[i] {
+            t.Errorf("word %d: got %+v, want %+v", i, words[i], want[i])
+        }
+    }
+}
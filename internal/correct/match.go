@@ -0,0 +1,106 @@
+package correct
+
+import (
+    _ "embed"
+    "strings"
+    "unicode"
+)
+
+//go:embed assets/common_words.txt
+var commonWordList string
+
+// commonWords is the set parsed from commonWordList.
+var commonWords = func() map[string]bool {
+    m := make(map[string]bool)
+    for _, line := range strings.Split(commonWordList, "\n") {
+        line = strings.TrimSpace(line)
+        if line != "" && !strings.HasPrefix(line, "#") {
+            m[normalize(line)] = true
+        }
+    }
+    return m
+}()
+
+// isCommonWord reports whether norm, a normalised single word, is everyday English.
+func isCommonWord(norm string) bool { return commonWords[norm] }
+
+// normalize lower-cases s and drops everything but letters and digits, so "Cooper
+// Netties" and "coopernetties" compare equal.
+func normalize(s string) string {
+    var b strings.Builder
+    for _, r := range strings.ToLower(s) {
+        if unicode.IsLetter(r) || unicode.IsDigit(r) {
+            b.WriteRune(r)
+        }
+    }
+    return b.String()
+}
+
+// soundexClass groups consonants that are easily confused by ear, as in Soundex.
+var soundexClass = map[rune]byte{
+    'b': '1', 'f': '1', 'p': '1', 'v': '1',
+    'c': '2', 'g': '2', 'j': '2', 'k': '2', 'q': '2', 's': '2', 'x': '2', 'z': '2',
+    'd': '3', 't': '3',
+    'l': '4',
+    'm': '5', 'n': '5',
+    'r': '6',
+}
+
+// phoneticKey is the word's consonant skeleton: every consonant (including the first,
+// unlike Soundex) mapped to its class, vowels dropped and repeats collapsed. Both
+// "kubernetes" and "coopernetties" give 216532.
+func phoneticKey(norm string) string {
+    var b []byte
+    for _, r := range norm {
+        c, ok := soundexClass[r]
+        if !ok {
+            if unicode.IsDigit(r) {
+                c = byte(r)
+            } else {
+                continue
+            }
+        }
+        if len(b) > 0 && b[len(b)-1] == c {
+            continue
+        }
+        b = append(b, c)
+    }
+    return string(b)
+}
+
+// sameOnset reports whether a and b begin with the same sound class; vowels only match
+// vowels.
+func sameOnset(a, b string) bool {
+    ra, rb := []rune(a), []rune(b)
+    if len(ra) == 0 || len(rb) == 0 {
+        return false
+    }
+    ca, okA := soundexClass[ra[0]]
+    cb, okB := soundexClass[rb[0]]
+    if !okA || !okB {
+        return !okA && !okB
+    }
+    return ca == cb
+}
+
+// levenshtein is the edit distance between a and b, counted in runes.
+func levenshtein(a, b string) int {
+    ra, rb := []rune(a), []rune(b)
+    prev := make([]int, len(rb)+1)
+    cur := make([]int, len(rb)+1)
+    for j := range prev {
+        prev[j] = j
+    }
+    for i := 1; i <= len(ra); i++ {
+        cur[0] = i
+        for j := 1; j <= len(rb); j++ {
+            cost := 1
+            if ra[i-1] == rb[j-1] {
+                cost = 0
+            }
+            cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+        }
+        prev, cur = cur, prev
+    }
+    return prev[len(rb)]
+}
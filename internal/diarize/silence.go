@@ -2,10 +2,15 @@ package diarize
 
 import (
     "context"
+    "strings"
+    "unicode"
+
     "github.com/zudsniper/meet-recording-processor/internal/transcribe"
 )
 
 // Silence is a minimal heuristic diarizer: alternate speakers when a gap exceeds a threshold.
+// With word timings, a pause inside a segment also starts a new turn, since Whisper often
+// runs a quick reply into the previous sentence's segment.
 // This is a placeholder and should be replaced with a proper diarization pipeline (e.g., pyannote, NeMo, or WhisperX + pyannote).
 type Silence struct{}
 
@@ -18,8 +23,14 @@ func (Silence) AssignSpeakers(ctx context.Context, tr *transcribe.Transcript) er
     for _, s := range tr.Segments { if s.Speaker != "" { hasSpeaker = true; break } }
     if hasSpeaker { return nil }
 
-    speaker := 1
     const gapThresh = 1.5 // seconds
+    var turns []transcribe.Segment
+    for _, s := range tr.Segments {
+        turns = append(turns, splitOnWordGaps(s, gapThresh)...)
+    }
+    tr.Segments = turns
+
+    speaker := 1
     for i := range tr.Segments {
         if i == 0 {
             tr.Segments[i].Speaker = speakerName(speaker)
@@ -35,6 +46,80 @@ func (Silence) AssignSpeakers(ctx context.Context, tr *transcribe.Transcript) er
     return nil
 }
 
+// splitOnWordGaps cuts s wherever consecutive words are more than gap seconds apart.
+// Each piece keeps its own words and the matching slice of the segment's text, so
+// punctuation and casing the words lack survive. When the words cannot be located in
+// the text, s is returned whole.
+func splitOnWordGaps(s transcribe.Segment, gap float64) []transcribe.Segment {
+    var cuts []int // indices of words that start a new piece
+    for i := 1; i < len(s.Words); i++ {
+        if s.Words[i].Start-s.Words[i-1].End > gap {
+            cuts = append(cuts, i)
+        }
+    }
+    if len(cuts) == 0 {
+        return []transcribe.Segment{s}
+    }
+    pos, ok := wordOffsets(s.Text, s.Words)
+    if !ok {
+        return []transcribe.Segment{s}
+    }
+
+    var out []transcribe.Segment
+    bounds := append(append([]int{0}, cuts...), len(s.Words))
+    for k := 0; k+1 < len(bounds); k++ {
+        from, to := bounds[k], bounds[k+1]
+        words := s.Words[from:to]
+        textFrom, textTo := 0, len(s.Text)
+        if from > 0 {
+            textFrom = pos[from]
+        }
+        if to < len(s.Words) {
+            textTo = pos[to]
+        }
+        piece := transcribe.Segment{
+            StartSec: words[0].Start,
+            EndSec:   words[len(words)-1].End,
+            Text:     strings.TrimSpace(s.Text[textFrom:textTo]),
+            Words:    words,
+        }
+        // the outer pieces keep the segment's own bounds
+        if from == 0 {
+            piece.StartSec = s.StartSec
+        }
+        if to == len(s.Words) {
+            piece.EndSec = s.EndSec
+        }
+        out = append(out, piece)
+    }
+    return out
+}
+
+// wordOffsets finds where each word starts in text, matching the words' letters and
+// digits in order and ignoring case and punctuation. ok is false when a word is missing.
+func wordOffsets(text string, words []transcribe.Word) ([]int, bool) {
+    lower := strings.ToLower(text)
+    if len(lower) != len(text) {
+        return nil, false // byte offsets would not carry over
+    }
+    pos := make([]int, len(words))
+    at := 0
+    for i, w := range words {
+        core := strings.ToLower(strings.TrimFunc(w.Text, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }))
+        if core == "" {
+            pos[i] = at
+            continue
+        }
+        k := strings.Index(lower[at:], core)
+        if k < 0 {
+            return nil, false
+        }
+        pos[i] = at + k
+        at += k + len(core)
+    }
+    return pos, true
+}
+
 func speakerName(i int) string {
     return map[int]string{1:"Speaker 1",2:"Speaker 2"}[i]
 }
@@ -0,0 +1,53 @@
+package diarize
+
+import (
+    "context"
+    "testing"
+
+    "github.com/zudsniper/meet-recording-processor/internal/transcribe"
+)
+
+func TestSilenceSplitsOnWordGaps(t *testing.T) {
+    // OpenAI and Cloudflare words come without punctuation or casing
+    tr := transcribe.Transcript{Segments: []transcribe.Segment{{
+        StartSec: 0, EndSec: 6,
+        Text: "Are we ready to ship? Yes, let's do it.",
+        Words: []transcribe.Word{
+            {Start: 0.1, End: 0.3, Text: "are"}, {Start: 0.3, End: 0.5, Text: "we"},
+            {Start: 0.5, End: 0.8, Text: "ready"}, {Start: 0.8, End: 0.9, Text: "to"},
+            {Start: 0.9, End: 1.3, Text: "ship"},
+            {Start: 3.2, End: 3.5, Text: "yes"}, {Start: 3.6, End: 3.9, Text: "let's"},
+            {Start: 3.9, End: 4.1, Text: "do"}, {Start: 4.1, End: 4.4, Text: "it"},
+        },
+    }}}
+    if err := (Silence{}).AssignSpeakers(context.Background(), &tr); err != nil {
+        t.Fatal(err)
+    }
+    want := []struct {
+        start, end    float64
+        text, speaker string
+    }{
+        {0, 1.3, "Are we ready to ship?", "Speaker 1"},
+        {3.2, 6, "Yes, let's do it.", "Speaker 2"},
+    }
+    if len(tr.Segments) != len(want) {
+        t.Fatalf("got %d segments: %+v", len(tr.Segments), tr.Segments)
+    }
+    for i, w := range want {
+        s := tr.Segments[i]
+        if s.StartSec != w.start || s.EndSec != w.end || s.Text != w.text || s.Speaker != w.speaker {
+            t.Errorf("segment %d = %+v", i, s)
+        }
+    }
+}
+
+func TestSilenceKeepsSegmentWhenWordsDoNotMatch(t *testing.T) {
+    s := transcribe.Segment{
+        StartSec: 0, EndSec: 5, Text: "Completely different text.",
+        Words: []transcribe.Word{{Start: 0, End: 1, Text: "hello"}, {Start: 3, End: 4, Text: "world"}},
+    }
+    got := splitOnWordGaps(s, 1.5)
+    if len(got) != 1 || got[0].Text != s.Text {
+        t.Errorf("got %+v", got)
+    }
+}
@@ -1,34 +1,87 @@
 package media
 
 import (
+    "bytes"
     "context"
     "fmt"
     "os"
     "os/exec"
     "path/filepath"
+    "strconv"
     "strings"
 )
 
+const (
+    AnyStream   = -1 // let ffmpeg pick the audio stream itself
+    AllChannels = -1 // downmix every channel to mono
+)
+
+// ExtractOptions controls ExtractAudio.
+type ExtractOptions struct {
+    TmpDir  string   // where the WAV is written (default system temp)
+    Stream  int      // absolute index of the audio stream (AudioStream.Index), or AnyStream
+    Channel int      // 0-based channel to keep (0 = left, 1 = right), or AllChannels
+    Filters []string // preprocessing filters from PreprocessFilters, applied after channel selection
+
+    // StartSec and EndSec limit extraction to part of the input; 0 means from the
+    // beginning and to the end respectively. The WAV then starts at StartSec.
+    StartSec float64
+    EndSec   float64
+}
+
 // ExtractAudio uses ffmpeg to extract mono 16kHz WAV from a video.
 // Returns the path to the extracted audio file.
-func ExtractAudio(ctx context.Context, videoPath string, tmpDir string) (string, error) {
+func ExtractAudio(ctx context.Context, videoPath string, opts ExtractOptions) (string, error) {
+    tmpDir := opts.TmpDir
     if tmpDir == "" {
         tmpDir = os.TempDir()
     }
-    base := strings.TrimSuffix(filepath.Base(videoPath), filepath.Ext(videoPath))
-    out := filepath.Join(tmpDir, base+"_audio_16k.wav")
+    // one file per stream/channel so per-track runs do not overwrite each other
+    name := strings.TrimSuffix(filepath.Base(videoPath), filepath.Ext(videoPath)) + "_audio"
+    if opts.Stream >= 0 {
+        name += "_s" + strconv.Itoa(opts.Stream)
+    }
+    if opts.Channel >= 0 {
+        name += "_c" + strconv.Itoa(opts.Channel)
+    }
+    out := filepath.Join(tmpDir, name+"_16k.wav")
 
-    // ffmpeg -y -i input -ac 1 -ar 16000 -f wav output
-    cmd := exec.CommandContext(ctx, "ffmpeg",
-        "-y", "-i", videoPath,
+    // ffmpeg -y [-ss S] -i input [-t D] [-map 0:N] [-af pan=mono|c0=cK,filters...] -ac 1 -ar 16000 -f wav output
+    args := []string{"-hide_banner", "-loglevel", "error", "-y"}
+    if opts.StartSec > 0 {
+        // seeking on the input skips decoding everything before the clip
+        args = append(args, "-ss", formatSec(opts.StartSec))
+    }
+    args = append(args, "-i", videoPath)
+    if opts.EndSec > 0 {
+        args = append(args, "-t", formatSec(opts.EndSec-opts.StartSec))
+    }
+    if opts.Stream >= 0 {
+        args = append(args, "-map", "0:"+strconv.Itoa(opts.Stream))
+    }
+    var af []string
+    if opts.Channel >= 0 {
+        af = append(af, fmt.Sprintf("pan=mono|c0=c%d", opts.Channel))
+    }
+    af = append(af, opts.Filters...)
+    if len(af) > 0 {
+        args = append(args, "-af", strings.Join(af, ","))
+    }
+    args = append(args,
+        "-vn",
         "-ac", "1", "-ar", "16000",
         "-f", "wav",
         out,
     )
-    cmd.Stdout = os.Stdout
-    cmd.Stderr = os.Stderr
+    cmd := exec.CommandContext(ctx, "ffmpeg", args...)
+    var stderr bytes.Buffer
+    cmd.Stderr = &stderr
     if err := cmd.Run(); err != nil {
-        return "", fmt.Errorf("ffmpeg: %w", err)
+        return "", fmt.Errorf("ffmpeg: %w%s", err, toolOutput(&stderr))
     }
     return out, nil
 }
+
+func formatSec(sec float64) string {
+    return strconv.FormatFloat(sec, 'f', 3, 64)
+}
@@ -0,0 +1,71 @@
+package media
+
+import (
+    "fmt"
+    "sort"
+    "strings"
+)
+
+// preprocessSteps are the ffmpeg audio filters allowed in a preprocessing chain, with
+// the parameters used when a step is named without any.
+var preprocessSteps = map[string]string{
+    "highpass":   "f=80",                 // cut rumble, HVAC and desk thumps below speech
+    "lowpass":    "f=8000",               // nothing above 8 kHz survives 16 kHz resampling anyway
+    "afftdn":     "nf=-25",               // FFT denoiser for steady hiss and fan noise
+    "arnndn":     "",                     // RNNoise denoiser; needs a model: arnndn=m=/path/model.rnnn
+    "dynaudnorm": "f=150:g=15",           // even out near and far talkers
+    "loudnorm":   "I=-16:TP=-1.5:LRA=11", // EBU R128 loudness normalisation
+}
+
+// Presets are named preprocessing chains for --preprocess.
+var Presets = map[string]string{
+    "none":         "",
+    "normalize":    "loudnorm",
+    "meeting-room": "highpass,afftdn,dynaudnorm,loudnorm",
+    "noisy":        "highpass=f=100,lowpass,afftdn=nf=-20,dynaudnorm,loudnorm",
+    "podcast":      "highpass=f=60,loudnorm",
+}
+
+// PresetNames lists the presets in alphabetical order, for help text.
+func PresetNames() []string {
+    names := make([]string, 0, len(Presets))
+    for n := range Presets {
+        names = append(names, n)
+    }
+    sort.Strings(names)
+    return names
+}
+
+// PreprocessFilters turns a preset name or a comma-separated chain of steps, each
+// optionally with ffmpeg parameters ("highpass=f=120,loudnorm"), into the filters
+// ExtractAudio applies. Steps named without parameters get sensible defaults.
+func PreprocessFilters(spec string) ([]string, error) {
+    spec = strings.TrimSpace(spec)
+    if chain, isPreset := Presets[strings.ToLower(spec)]; isPreset {
+        spec = chain
+    }
+    if spec == "" {
+        return nil, nil
+    }
+    var filters []string
+    for _, step := range strings.Split(spec, ",") {
+        step = strings.TrimSpace(step)
+        name, params, _ := strings.Cut(step, "=")
+        def, known := preprocessSteps[name]
+        if !known {
+            return nil, fmt.Errorf("unknown preprocessing step %q (presets: %s; steps: highpass, lowpass, afftdn, arnndn, dynaudnorm, loudnorm)",
+                step, strings.Join(PresetNames(), ", "))
+        }
+        if params == "" {
+            params = def
+        }
+        if name == "arnndn" && !strings.Contains(params, "m=") && !strings.Contains(params, "model=") {
+            return nil, fmt.Errorf("arnndn needs an RNNoise model, e.g. arnndn=m=/path/to/model.rnnn")
+        }
+        if params != "" {
+            name += "=" + params
+        }
+        filters = append(filters, name)
+    }
+    return filters, nil
+}
@@ -0,0 +1,192 @@
+package media
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "os/exec"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// ErrNoAudio reports a file without any audio stream to transcribe.
+var ErrNoAudio = errors.New("no audio stream")
+
+// Info describes a media file as reported by ffprobe.
+type Info struct {
+    Format   string        // container, e.g. "mov,mp4,m4a,3gp,3g2,mj2" or "matroska,webm"
+    Duration time.Duration // container duration; 0 when unknown
+    Created  time.Time     // creation_time tag of the container or first stream; zero when absent
+    Audio    []AudioStream
+    Video    []VideoStream
+}
+
+// AudioStream is one audio track. Index is the stream's absolute index in the file,
+// as used by ffmpeg's -map 0:<index>.
+type AudioStream struct {
+    Index         int
+    Codec         string
+    Channels      int
+    ChannelLayout string
+    SampleRate    int
+    Language      string // language tag, e.g. "eng"; empty when untagged
+    Title         string
+    Default       bool // marked as the default track
+}
+
+// VideoStream is one video track.
+type VideoStream struct {
+    Index     int
+    Codec     string
+    Width     int
+    Height    int
+    FrameRate float64
+}
+
+// DefaultAudio returns the audio stream a player would pick: the one flagged default,
+// else the first. ok is false when the file has no audio.
+func (in *Info) DefaultAudio() (AudioStream, bool) {
+    if len(in.Audio) == 0 {
+        return AudioStream{}, false
+    }
+    for _, a := range in.Audio {
+        if a.Default {
+            return a, true
+        }
+    }
+    return in.Audio[0], true
+}
+
+type ffprobeOutput struct {
+    Streams []struct {
+        Index         int               `json:"index"`
+        CodecType     string            `json:"codec_type"`
+        CodecName     string            `json:"codec_name"`
+        Channels      int               `json:"channels"`
+        ChannelLayout string            `json:"channel_layout"`
+        SampleRate    string            `json:"sample_rate"`
+        Width         int               `json:"width"`
+        Height        int               `json:"height"`
+        AvgFrameRate  string            `json:"avg_frame_rate"`
+        Disposition   map[string]int    `json:"disposition"`
+        Tags          map[string]string `json:"tags"`
+    } `json:"streams"`
+    Format struct {
+        FormatName string            `json:"format_name"`
+        Duration   string            `json:"duration"`
+        Tags       map[string]string `json:"tags"`
+    } `json:"format"`
+}
+
+// Probe runs ffprobe on path and returns its container and stream details.
+func Probe(ctx context.Context, path string) (*Info, error) {
+    cmd := exec.CommandContext(ctx, "ffprobe",
+        "-v", "error",
+        "-print_format", "json",
+        "-show_format", "-show_streams",
+        path,
+    )
+    var stdout, stderr bytes.Buffer
+    cmd.Stdout = &stdout
+    cmd.Stderr = &stderr
+    if err := cmd.Run(); err != nil {
+        return nil, fmt.Errorf("ffprobe: %w%s", err, toolOutput(&stderr))
+    }
+    var out ffprobeOutput
+    if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+        return nil, fmt.Errorf("ffprobe: parse output: %w", err)
+    }
+
+    in := &Info{Format: out.Format.FormatName}
+    if sec, err := strconv.ParseFloat(out.Format.Duration, 64); err == nil {
+        in.Duration = time.Duration(sec * float64(time.Second))
+    }
+    in.Created = parseCreationTime(out.Format.Tags)
+    for _, s := range out.Streams {
+        if in.Created.IsZero() {
+            in.Created = parseCreationTime(s.Tags)
+        }
+        switch s.CodecType {
+        case "audio":
+            rate, _ := strconv.Atoi(s.SampleRate)
+            in.Audio = append(in.Audio, AudioStream{
+                Index:         s.Index,
+                Codec:         s.CodecName,
+                Channels:      s.Channels,
+                ChannelLayout: s.ChannelLayout,
+                SampleRate:    rate,
+                Language:      tag(s.Tags, "language"),
+                Title:         tag(s.Tags, "title"),
+                Default:       s.Disposition["default"] == 1,
+            })
+        case "video":
+            // cover art in audio files shows up as a single-frame video stream
+            if s.Disposition["attached_pic"] == 1 {
+                continue
+            }
+            in.Video = append(in.Video, VideoStream{
+                Index:     s.Index,
+                Codec:     s.CodecName,
+                Width:     s.Width,
+                Height:    s.Height,
+                FrameRate: parseRate(s.AvgFrameRate),
+            })
+        }
+    }
+    return in, nil
+}
+
+// tag looks up an ffprobe tag; containers differ in the case they use for keys.
+func tag(tags map[string]string, key string) string {
+    for k, v := range tags {
+        if strings.EqualFold(k, key) {
+            return v
+        }
+    }
+    return ""
+}
+
+func parseCreationTime(tags map[string]string) time.Time {
+    v := tag(tags, "creation_time")
+    if v == "" {
+        return time.Time{}
+    }
+    for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05"} {
+        if t, err := time.Parse(layout, v); err == nil {
+            return t
+        }
+    }
+    return time.Time{}
+}
+
+// parseRate turns ffprobe's "30000/1001" into frames per second.
+func parseRate(r string) float64 {
+    num, den, found := strings.Cut(r, "/")
+    n, err := strconv.ParseFloat(num, 64)
+    if err != nil {
+        return 0
+    }
+    if !found {
+        return n
+    }
+    d, err := strconv.ParseFloat(den, 64)
+    if err != nil || d == 0 {
+        return 0
+    }
+    return n / d
+}
+
+// toolOutput formats the last lines a failed ffmpeg/ffprobe wrote, for error messages.
+func toolOutput(b *bytes.Buffer) string {
+    lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+    if len(lines) == 1 && lines[0] == "" {
+        return ""
+    }
+    if len(lines) > 5 {
+        lines = lines[len(lines)-5:]
+    }
+    return "\n" + strings.Join(lines, "\n")
+}
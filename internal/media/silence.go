@@ -0,0 +1,200 @@
+package media
+
+import (
+    "fmt"
+    "math"
+    "os"
+    "path/filepath"
+    "sort"
+    "strings"
+)
+
+// TrimOptions controls TrimSilence.
+type TrimOptions struct {
+    Edges        bool    // drop silence before the first and after the last speech
+    MaxGapSec    float64 // shorten internal silences longer than this; 0 keeps them
+    PadSec       float64 // audio kept either side of speech (default 0.5s)
+    ThresholdDB  float64 // frames quieter than this (dBFS) are silent; 0 adapts to the noise floor
+    MinSpeechSec float64 // louder blips shorter than this still count as silence (default 0.3s)
+}
+
+// Span is a piece of trimmed audio and where it came from in the source.
+type Span struct {
+    OutSec float64 // start in the trimmed audio
+    SrcSec float64 // start in the source audio
+    LenSec float64
+}
+
+// TimeMap converts timestamps in trimmed audio back to the source. A nil map is the
+// identity.
+type TimeMap []Span
+
+// Source maps a start/end pair from trimmed to source time. A boundary between two
+// spans belongs to the later span for start times and the earlier one for end times,
+// so a segment ending right at a cut does not stretch across the removed silence.
+func (m TimeMap) Source(start, end float64) (float64, float64) {
+    return m.at(start, false), m.at(end, true)
+}
+
+func (m TimeMap) at(t float64, closing bool) float64 {
+    if len(m) == 0 {
+        return t
+    }
+    i := sort.Search(len(m), func(i int) bool {
+        end := m[i].OutSec + m[i].LenSec
+        if closing {
+            return end >= t
+        }
+        return end > t
+    })
+    if i == len(m) {
+        i = len(m) - 1
+    }
+    return m[i].SrcSec + (t - m[i].OutSec)
+}
+
+// RemovedSec is how much audio the trim cut out of a source of totalSec seconds.
+func (m TimeMap) RemovedSec(totalSec float64) float64 {
+    if len(m) == 0 {
+        return 0
+    }
+    kept := 0.0
+    for _, s := range m {
+        kept += s.LenSec
+    }
+    return totalSec - kept
+}
+
+const vadFrameSec = 0.05
+
+// TrimSilence writes a copy of the WAV at path without its leading/trailing silence
+// and/or with long pauses shortened, using a simple energy threshold. It returns the
+// new file and the map back to the original timeline; when nothing would be removed,
+// or no speech is found at all, it returns path itself and a nil map.
+func TrimSilence(path string, tmpDir string, opts TrimOptions) (string, TimeMap, error) {
+    if opts.PadSec <= 0 {
+        opts.PadSec = 0.5
+    }
+    if opts.MinSpeechSec <= 0 {
+        opts.MinSpeechSec = 0.3
+    }
+    info, err := ReadWAVInfo(path)
+    if err != nil {
+        return "", nil, err
+    }
+    energies, err := FrameEnergies(path, info, vadFrameSec)
+    if err != nil {
+        return "", nil, fmt.Errorf("analyse audio: %w", err)
+    }
+    total := info.DurationSec()
+
+    keep := speechRanges(energies, opts, total)
+    if len(keep) == 0 || (len(keep) == 1 && keep[0].StartSec <= 0 && keep[0].EndSec >= total) {
+        return path, nil, nil
+    }
+
+    if tmpDir == "" {
+        tmpDir = os.TempDir()
+    }
+    base := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+    out := filepath.Join(tmpDir, base+"_trimmed.wav")
+    if err := WriteWAVRanges(path, info, keep, out); err != nil {
+        return "", nil, err
+    }
+    var m TimeMap
+    pos := 0.0
+    for _, r := range keep {
+        m = append(m, Span{OutSec: pos, SrcSec: r.StartSec, LenSec: r.EndSec - r.StartSec})
+        pos += r.EndSec - r.StartSec
+    }
+    return out, m, nil
+}
+
+// speechRanges returns the source ranges to keep: everything between the first and last
+// speech (or the whole file when Edges is off), minus the middle of any pause longer
+// than MaxGapSec.
+func speechRanges(energies []float64, opts TrimOptions, total float64) []Range {
+    thr := silenceThreshold(energies, opts.ThresholdDB)
+    voiced := make([]bool, len(energies))
+    for i, e := range energies {
+        voiced[i] = e >= thr
+    }
+    // drop blips (clicks, a cough in an empty room) that are too short to be speech
+    minFrames := int(math.Ceil(opts.MinSpeechSec / vadFrameSec))
+    for i := 0; i < len(voiced); {
+        if !voiced[i] {
+            i++
+            continue
+        }
+        j := i
+        for j < len(voiced) && voiced[j] {
+            j++
+        }
+        if j-i < minFrames {
+            for k := i; k < j; k++ {
+                voiced[k] = false
+            }
+        }
+        i = j
+    }
+
+    // speech runs in seconds, padded and merged
+    var runs []Range
+    for i := 0; i < len(voiced); {
+        if !voiced[i] {
+            i++
+            continue
+        }
+        j := i
+        for j < len(voiced) && voiced[j] {
+            j++
+        }
+        r := Range{StartSec: math.Max(0, float64(i)*vadFrameSec-opts.PadSec), EndSec: math.Min(total, float64(j)*vadFrameSec+opts.PadSec)}
+        if n := len(runs); n > 0 && r.StartSec <= runs[n-1].EndSec {
+            runs[n-1].EndSec = r.EndSec
+        } else {
+            runs = append(runs, r)
+        }
+        i = j
+    }
+    if len(runs) == 0 {
+        return nil
+    }
+
+    if !opts.Edges {
+        runs[0].StartSec = 0
+        runs[len(runs)-1].EndSec = total
+    }
+    if opts.MaxGapSec <= 0 {
+        // only the edges go; keep every internal pause
+        return []Range{{StartSec: runs[0].StartSec, EndSec: runs[len(runs)-1].EndSec}}
+    }
+    // gaps up to MaxGapSec (measured between the padded runs) are kept whole
+    keep := []Range{runs[0]}
+    for _, r := range runs[1:] {
+        last := &keep[len(keep)-1]
+        if r.StartSec-last.EndSec <= opts.MaxGapSec {
+            last.EndSec = r.EndSec
+        } else {
+            keep = append(keep, r)
+        }
+    }
+    return keep
+}
+
+// silenceThreshold returns the RMS level separating silence from speech: the given
+// dBFS value, or 10 dB above the noise floor (the 10th-percentile frame), clamped to
+// -60..-35 dBFS so neither digital silence nor a noisy room throws it off.
+func silenceThreshold(energies []float64, db float64) float64 {
+    if db < 0 {
+        return math.Pow(10, db/20)
+    }
+    if len(energies) == 0 {
+        return 0
+    }
+    sorted := append([]float64(nil), energies...)
+    sort.Float64s(sorted)
+    floor := sorted[len(sorted)/10]
+    thr := floor * math.Pow(10, 10.0/20)
+    return math.Min(math.Max(thr, math.Pow(10, -60.0/20)), math.Pow(10, -35.0/20))
+}
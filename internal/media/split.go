@@ -0,0 +1,95 @@
+package media
+
+import (
+    "context"
+    "fmt"
+    "os"
+    "path/filepath"
+    "strings"
+)
+
+// Chunk is one piece of a longer WAV file produced by SplitWAV.
+type Chunk struct {
+    Path     string
+    StartSec float64 // start of the chunk in the source audio, including overlap
+    EndSec   float64 // end of the chunk in the source audio
+    KeepSec  float64 // boundary with the previous chunk; audio before it is overlap
+}
+
+// SplitOptions controls how SplitWAV picks chunk boundaries.
+type SplitOptions struct {
+    MaxDurationSec float64 // upper bound on a chunk's length, overlap included
+    OverlapSec     float64 // audio repeated from the previous chunk to avoid cutting words
+    SearchSec      float64 // how far back from the hard limit to look for a quiet cut point
+}
+
+const splitFrameSec = 0.1
+
+// SplitWAV cuts a 16-bit PCM WAV into chunks no longer than opts.MaxDurationSec,
+// preferring the quietest point within opts.SearchSec of each limit so cuts land
+// on pauses rather than mid-word. Chunk files are written to tmpDir.
+func SplitWAV(ctx context.Context, path string, tmpDir string, opts SplitOptions) ([]Chunk, error) {
+    info, err := ReadWAVInfo(path)
+    if err != nil {
+        return nil, err
+    }
+    total := info.DurationSec()
+    if opts.MaxDurationSec <= 0 || total <= opts.MaxDurationSec {
+        return []Chunk{{Path: path, StartSec: 0, EndSec: total, KeepSec: 0}}, nil
+    }
+    if opts.OverlapSec < 0 { opts.OverlapSec = 0 }
+    if opts.OverlapSec >= opts.MaxDurationSec/2 {
+        return nil, fmt.Errorf("chunk overlap %.1fs too large for %.1fs chunks", opts.OverlapSec, opts.MaxDurationSec)
+    }
+    if opts.SearchSec <= 0 || opts.SearchSec > opts.MaxDurationSec/2 {
+        opts.SearchSec = opts.MaxDurationSec / 4
+    }
+
+    energies, err := FrameEnergies(path, info, splitFrameSec)
+    if err != nil {
+        return nil, fmt.Errorf("analyse audio: %w", err)
+    }
+
+    if tmpDir == "" {
+        tmpDir = os.TempDir()
+    }
+    base := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+    var chunks []Chunk
+    prevCut := 0.0
+    for i := 0; prevCut < total; i++ {
+        if err := ctx.Err(); err != nil {
+            return nil, err
+        }
+        start := prevCut - opts.OverlapSec
+        if i == 0 || start < 0 { start = 0 }
+        end := start + opts.MaxDurationSec
+        if end >= total {
+            end = total
+        } else {
+            end = quietestPoint(energies, end-opts.SearchSec, end)
+        }
+        out := filepath.Join(tmpDir, fmt.Sprintf("%s_chunk%03d.wav", base, i))
+        if err := WriteWAVRange(path, info, start, end, out); err != nil {
+            return nil, fmt.Errorf("write chunk %d: %w", i, err)
+        }
+        chunks = append(chunks, Chunk{Path: out, StartSec: start, EndSec: end, KeepSec: prevCut})
+        prevCut = end
+    }
+    return chunks, nil
+}
+
+// quietestPoint returns the centre of the lowest-energy frame between from and to.
+func quietestPoint(energies []float64, from, to float64) float64 {
+    lo, hi := int(from/splitFrameSec), int(to/splitFrameSec)
+    if hi > len(energies) { hi = len(energies) }
+    if lo < 0 { lo = 0 }
+    if lo >= hi { return to }
+    best := hi - 1
+    for i := hi - 1; i >= lo; i-- {
+        if energies[i] < energies[best] { best = i }
+    }
+    cut := (float64(best) + 0.5) * splitFrameSec
+    if cut > to { cut = to }
+    return cut
+}
@@ -0,0 +1,221 @@
+package media
+
+import (
+    "bufio"
+    "encoding/binary"
+    "errors"
+    "fmt"
+    "io"
+    "math"
+    "os"
+)
+
+// WAVInfo describes the PCM payload of a WAV file.
+type WAVInfo struct {
+    SampleRate    int
+    Channels      int
+    BitsPerSample int
+    DataOffset    int64 // byte offset of the first sample
+    DataSize      int64 // length of the sample data in bytes
+}
+
+// BlockAlign is the size in bytes of one frame (one sample for every channel).
+func (w WAVInfo) BlockAlign() int64 { return int64(w.Channels * w.BitsPerSample / 8) }
+
+// ByteRate is the number of data bytes per second of audio.
+func (w WAVInfo) ByteRate() int64 { return int64(w.SampleRate) * w.BlockAlign() }
+
+// DurationSec is the length of the audio in seconds.
+func (w WAVInfo) DurationSec() float64 {
+    if w.ByteRate() == 0 { return 0 }
+    return float64(w.DataSize) / float64(w.ByteRate())
+}
+
+// offsetFor converts a timestamp into a frame-aligned byte offset relative to the data start.
+func (w WAVInfo) offsetFor(sec float64) int64 {
+    if sec <= 0 { return 0 }
+    off := int64(sec*float64(w.SampleRate)) * w.BlockAlign()
+    if off > w.DataSize { off = w.DataSize }
+    return off
+}
+
+// ReadWAVInfo parses the RIFF header of a PCM WAV file (as produced by ExtractAudio).
+func ReadWAVInfo(path string) (WAVInfo, error) {
+    f, err := os.Open(path)
+    if err != nil {
+        return WAVInfo{}, err
+    }
+    defer f.Close()
+    fi, err := f.Stat()
+    if err != nil {
+        return WAVInfo{}, err
+    }
+
+    var riff [12]byte
+    if _, err := io.ReadFull(f, riff[:]); err != nil {
+        return WAVInfo{}, fmt.Errorf("read wav header: %w", err)
+    }
+    if string(riff[0:4]) != "RIFF" || string(riff[8:12]) != "WAVE" {
+        return WAVInfo{}, errors.New("not a RIFF/WAVE file")
+    }
+
+    var info WAVInfo
+    var haveFmt bool
+    pos := int64(12)
+    for {
+        var hdr [8]byte
+        if _, err := io.ReadFull(f, hdr[:]); err != nil {
+            return WAVInfo{}, fmt.Errorf("wav data chunk not found: %w", err)
+        }
+        id := string(hdr[0:4])
+        size := int64(binary.LittleEndian.Uint32(hdr[4:8]))
+        pos += 8
+        switch id {
+        case "fmt ":
+            buf := make([]byte, size)
+            if _, err := io.ReadFull(f, buf); err != nil {
+                return WAVInfo{}, fmt.Errorf("read wav fmt chunk: %w", err)
+            }
+            if len(buf) < 16 {
+                return WAVInfo{}, errors.New("wav fmt chunk too short")
+            }
+            if format := binary.LittleEndian.Uint16(buf[0:2]); format != 1 && format != 0xFFFE {
+                return WAVInfo{}, fmt.Errorf("unsupported wav format %d (want PCM)", format)
+            }
+            info.Channels = int(binary.LittleEndian.Uint16(buf[2:4]))
+            info.SampleRate = int(binary.LittleEndian.Uint32(buf[4:8]))
+            info.BitsPerSample = int(binary.LittleEndian.Uint16(buf[14:16]))
+            haveFmt = true
+        case "data":
+            if !haveFmt {
+                return WAVInfo{}, errors.New("wav data chunk before fmt chunk")
+            }
+            info.DataOffset = pos
+            // Streaming writers may leave the size unset; trust the file length instead.
+            if size == 0 || size == 0xFFFFFFFF || pos+size > fi.Size() {
+                size = fi.Size() - pos
+            }
+            info.DataSize = size - size%info.BlockAlign()
+            if info.BitsPerSample != 16 {
+                return WAVInfo{}, fmt.Errorf("unsupported wav bit depth %d (want 16)", info.BitsPerSample)
+            }
+            return info, nil
+        default:
+            if _, err := f.Seek(size, io.SeekCurrent); err != nil {
+                return WAVInfo{}, err
+            }
+        }
+        pos += size
+        // chunks are word aligned
+        if size%2 == 1 {
+            if _, err := f.Seek(1, io.SeekCurrent); err != nil {
+                return WAVInfo{}, err
+            }
+            pos++
+        }
+    }
+}
+
+// WriteWAVRange copies the audio between startSec and endSec of src into a new WAV file at dst.
+func WriteWAVRange(src string, info WAVInfo, startSec, endSec float64, dst string) error {
+    return WriteWAVRanges(src, info, []Range{{StartSec: startSec, EndSec: endSec}}, dst)
+}
+
+// Range is a stretch of audio in seconds.
+type Range struct {
+    StartSec float64
+    EndSec   float64
+}
+
+// WriteWAVRanges concatenates the given ranges of src, in order, into a new WAV file at dst.
+func WriteWAVRanges(src string, info WAVInfo, ranges []Range, dst string) error {
+    in, err := os.Open(src)
+    if err != nil {
+        return err
+    }
+    defer in.Close()
+
+    var size int64
+    for _, r := range ranges {
+        from, to := info.offsetFor(r.StartSec), info.offsetFor(r.EndSec)
+        if to <= from {
+            return fmt.Errorf("empty wav range %.2f-%.2f", r.StartSec, r.EndSec)
+        }
+        size += to - from
+    }
+    out, err := os.Create(dst)
+    if err != nil {
+        return err
+    }
+    w := bufio.NewWriterSize(out, 1<<16)
+    if err := writeWAVHeader(w, info, size); err != nil {
+        out.Close()
+        return err
+    }
+    for _, r := range ranges {
+        from, to := info.offsetFor(r.StartSec), info.offsetFor(r.EndSec)
+        if _, err := io.Copy(w, io.NewSectionReader(in, info.DataOffset+from, to-from)); err != nil {
+            out.Close()
+            return err
+        }
+    }
+    if err := w.Flush(); err != nil {
+        out.Close()
+        return err
+    }
+    return out.Close()
+}
+
+func writeWAVHeader(w io.Writer, info WAVInfo, dataSize int64) error {
+    var h [44]byte
+    copy(h[0:4], "RIFF")
+    binary.LittleEndian.PutUint32(h[4:8], uint32(36+dataSize))
+    copy(h[8:12], "WAVE")
+    copy(h[12:16], "fmt ")
+    binary.LittleEndian.PutUint32(h[16:20], 16)
+    binary.LittleEndian.PutUint16(h[20:22], 1)
+    binary.LittleEndian.PutUint16(h[22:24], uint16(info.Channels))
+    binary.LittleEndian.PutUint32(h[24:28], uint32(info.SampleRate))
+    binary.LittleEndian.PutUint32(h[28:32], uint32(info.ByteRate()))
+    binary.LittleEndian.PutUint16(h[32:34], uint16(info.BlockAlign()))
+    binary.LittleEndian.PutUint16(h[34:36], uint16(info.BitsPerSample))
+    copy(h[36:40], "data")
+    binary.LittleEndian.PutUint32(h[40:44], uint32(dataSize))
+    _, err := w.Write(h[:])
+    return err
+}
+
+// FrameEnergies returns the RMS level (0..1) of consecutive frames of frameSec seconds.
+func FrameEnergies(path string, info WAVInfo, frameSec float64) ([]float64, error) {
+    f, err := os.Open(path)
+    if err != nil {
+        return nil, err
+    }
+    defer f.Close()
+
+    frameBytes := int64(frameSec*float64(info.SampleRate)) * info.BlockAlign()
+    if frameBytes <= 0 {
+        return nil, errors.New("frame size too small")
+    }
+    r := bufio.NewReaderSize(io.NewSectionReader(f, info.DataOffset, info.DataSize), 1<<16)
+    buf := make([]byte, frameBytes)
+    var out []float64
+    for {
+        n, err := io.ReadFull(r, buf)
+        if n >= 2 {
+            var sum float64
+            samples := n / 2
+            for i := 0; i < samples; i++ {
+                v := float64(int16(binary.LittleEndian.Uint16(buf[2*i:]))) / 32768
+                sum += v * v
+            }
+            out = append(out, math.Sqrt(sum/float64(samples)))
+        }
+        if err == io.EOF || err == io.ErrUnexpectedEOF {
+            return out, nil
+        }
+        if err != nil {
+            return nil, err
+        }
+    }
+}
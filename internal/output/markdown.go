@@ -9,16 +9,24 @@ import (
 )
 
 type Metadata struct {
-    Title     string
-    Desc      string
-    Attendees []string
-    Source    string
-    Backend   string
-    Model     string
-    Generated string
+    Title      string
+    Desc       string
+    Attendees  []string
+    Source     string
+    Backend    string
+    Model      string
+    Preprocess string // audio filters applied before transcription, for reproducibility
+    Recorded   string // when the recording was made, if the file says
+    Clip       string // the part of the recording transcribed (--start/--end), e.g. "30:00 – end"
+    Generated  string
 }
 
-func RenderMarkdown(meta Metadata, tr transcribe.Transcript) string {
+// RenderOptions selects optional parts of the rendered transcript.
+type RenderOptions struct {
+    WordTimings bool // append each segment's words with their start times, when the backend reported them
+}
+
+func RenderMarkdown(meta Metadata, tr transcribe.Transcript, opts RenderOptions) string {
     var b strings.Builder
     // Header
     if meta.Title != "" {
@@ -41,12 +49,31 @@ func RenderMarkdown(meta Metadata, tr transcribe.Transcript) string {
     if meta.Model != "" {
         fmt.Fprintf(&b, "- Model: `%s`\n", meta.Model)
     }
+    if meta.Preprocess != "" {
+        fmt.Fprintf(&b, "- Preprocessing: `%s`\n", meta.Preprocess)
+    }
+    if tr.Device != "" {
+        dev := tr.Device
+        if tr.ComputeType != "" {
+            dev += " (" + tr.ComputeType + ")"
+        }
+        fmt.Fprintf(&b, "- Device: `%s`\n", dev)
+    }
+    if meta.Recorded != "" {
+        fmt.Fprintf(&b, "- Recorded: %s\n", meta.Recorded)
+    }
+    if meta.Clip != "" {
+        fmt.Fprintf(&b, "- Clip: %s\n", meta.Clip)
+    }
     if meta.Generated != "" {
         fmt.Fprintf(&b, "- Generated: %s\n", meta.Generated)
     }
     if tr.Duration > 0 {
         fmt.Fprintf(&b, "- Duration: %s\n", tr.Duration.Truncate(time.Second))
     }
+    if lang := languageLine(tr); lang != "" {
+        fmt.Fprintf(&b, "- Language: %s\n", lang)
+    }
     b.WriteString("\n---\n\n")
 
     // Body
@@ -61,9 +88,49 @@ func RenderMarkdown(meta Metadata, tr transcribe.Transcript) string {
         }
         fmt.Fprintf(&b, "%s%s%s\n\n", ts, spk, strings.TrimSpace(s.Text))
     }
+    if opts.WordTimings {
+        writeWordTimings(&b, tr)
+    }
     return b.String()
 }
 
+// writeWordTimings lists every timed word as "[mm:ss.cc] word", one paragraph per
+// segment, for click-to-seek players and checking alignment.
+func writeWordTimings(b *strings.Builder, tr transcribe.Transcript) {
+    header := false
+    for _, s := range tr.Segments {
+        if len(s.Words) == 0 {
+            continue
+        }
+        if !header {
+            b.WriteString("---\n\n## Word Timings\n\n")
+            header = true
+        }
+        parts := make([]string, len(s.Words))
+        for i, w := range s.Words {
+            parts[i] = fmt.Sprintf("[%s] %s", secToPreciseTS(w.Start), w.Text)
+        }
+        fmt.Fprintf(b, "%s\n\n", strings.Join(parts, " "))
+    }
+}
+
+// languageLine describes the spoken language and, for translations, the output language.
+func languageLine(tr transcribe.Transcript) string {
+    if tr.OutputLanguage == "" || tr.OutputLanguage == tr.Language {
+        return tr.Language
+    }
+    if tr.Language == "" {
+        return tr.OutputLanguage + " (translated)"
+    }
+    return tr.Language + " → " + tr.OutputLanguage + " (translated)"
+}
+
+// secToPreciseTS is secToTS with hundredths of a second, e.g. 01:02.35.
+func secToPreciseTS(sec float64) string {
+    cs := int(sec*100 + 0.5)
+    return fmt.Sprintf("%s.%02d", secToTS(float64(cs/100)), cs%100)
+}
+
 func secToTS(sec float64) string {
     d := time.Duration(sec*1000) * time.Millisecond
     h := int(d.Hours())
@@ -0,0 +1,218 @@
+package transcribe
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "fmt"
+    "io"
+    "net/http"
+    "os"
+    "strings"
+    "time"
+)
+
+// DefaultAssemblyAIBaseURL is the API root used when AssemblyAIOptions.BaseURL is empty.
+const DefaultAssemblyAIBaseURL = "https://api.assemblyai.com"
+
+// AssemblyAIOptions configures the AssemblyAI provider.
+type AssemblyAIOptions struct {
+    APIKey        string
+    BaseURL       string
+    SpeechModel   string // e.g. best, nano, universal; empty uses the service default
+    Language      string // language_code; empty enables automatic language detection
+    SpeakerLabels bool
+    Retry         RetryPolicy
+}
+
+// assemblyAI implements AsyncProvider for AssemblyAI's /v2/upload and /v2/transcript API.
+type assemblyAI struct {
+    opts AssemblyAIOptions
+    hc   *http.Client
+}
+
+// NewAssemblyAIBackend returns a Backend transcribing with AssemblyAI.
+func NewAssemblyAIBackend(opts AssemblyAIOptions, async AsyncOptions) Backend {
+    if opts.BaseURL == "" {
+        opts.BaseURL = DefaultAssemblyAIBaseURL
+    }
+    opts.BaseURL = strings.TrimSuffix(opts.BaseURL, "/")
+    p := &assemblyAI{opts: opts, hc: &http.Client{Timeout: 60 * time.Minute}}
+    return NewAsyncBackend("assemblyai", p, async)
+}
+
+func (a *assemblyAI) Fingerprint() string {
+    o := a.opts
+    return fmt.Sprintf("assemblyai base=%s model=%s language=%s speakers=%t", o.BaseURL, o.SpeechModel, o.Language, o.SpeakerLabels)
+}
+
+// do sends one API request with retries and decodes the JSON response into out.
+func (a *assemblyAI) do(ctx context.Context, method, path string, body []byte, contentType string, out any) error {
+    resp, err := doWithRetry(ctx, a.hc, a.opts.Retry, "assemblyai", func() (*http.Request, error) {
+        var r io.Reader
+        if body != nil {
+            r = bytes.NewReader(body)
+        }
+        req, err := http.NewRequestWithContext(ctx, method, a.opts.BaseURL+path, r)
+        if err != nil {
+            return nil, err
+        }
+        req.Header.Set("Authorization", a.opts.APIKey)
+        if contentType != "" {
+            req.Header.Set("Content-Type", contentType)
+        }
+        return req, nil
+    })
+    if err != nil {
+        return err
+    }
+    defer resp.Body.Close()
+    if out == nil {
+        return nil
+    }
+    return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (a *assemblyAI) Upload(ctx context.Context, audioPath string) (string, error) {
+    audio, err := os.ReadFile(audioPath)
+    if err != nil {
+        return "", err
+    }
+    var out struct {
+        UploadURL string `json:"upload_url"`
+    }
+    if err := a.do(ctx, http.MethodPost, "/v2/upload", audio, "application/octet-stream", &out); err != nil {
+        return "", err
+    }
+    if out.UploadURL == "" {
+        return "", fmt.Errorf("upload returned no url")
+    }
+    return out.UploadURL, nil
+}
+
+type aaiJobRequest struct {
+    AudioURL          string `json:"audio_url"`
+    SpeechModel       string `json:"speech_model,omitempty"`
+    LanguageCode      string `json:"language_code,omitempty"`
+    LanguageDetection bool   `json:"language_detection,omitempty"`
+    SpeakerLabels     bool   `json:"speaker_labels"`
+    Punctuate         bool   `json:"punctuate"`
+    FormatText        bool   `json:"format_text"`
+}
+
+func (a *assemblyAI) Submit(ctx context.Context, audioRef string) (string, error) {
+    body, err := json.Marshal(aaiJobRequest{
+        AudioURL:          audioRef,
+        SpeechModel:       a.opts.SpeechModel,
+        LanguageCode:      a.opts.Language,
+        LanguageDetection: a.opts.Language == "",
+        SpeakerLabels:     a.opts.SpeakerLabels,
+        Punctuate:         true,
+        FormatText:        true,
+    })
+    if err != nil {
+        return "", err
+    }
+    var out struct {
+        ID string `json:"id"`
+    }
+    if err := a.do(ctx, http.MethodPost, "/v2/transcript", body, "application/json", &out); err != nil {
+        return "", err
+    }
+    if out.ID == "" {
+        return "", fmt.Errorf("submit returned no job id")
+    }
+    return out.ID, nil
+}
+
+// aaiWord and aaiJob cover the parts of a transcript object we use. Times are milliseconds.
+type aaiWord struct {
+    Text       string  `json:"text"`
+    Start      int64   `json:"start"`
+    End        int64   `json:"end"`
+    Confidence float64 `json:"confidence"`
+}
+
+type aaiJob struct {
+    Status        string    `json:"status"` // queued|processing|completed|error
+    Error         string    `json:"error"`
+    Text          string    `json:"text"`
+    LanguageCode  string    `json:"language_code"`
+    AudioDuration float64   `json:"audio_duration"` // seconds
+    Words         []aaiWord `json:"words"`
+    Utterances    []struct {
+        Speaker string    `json:"speaker"`
+        Start   int64     `json:"start"`
+        End     int64     `json:"end"`
+        Text    string    `json:"text"`
+        Words   []aaiWord `json:"words"`
+    } `json:"utterances"`
+}
+
+func (a *assemblyAI) Poll(ctx context.Context, jobID string) (bool, Transcript, error) {
+    var job aaiJob
+    if err := a.do(ctx, http.MethodGet, "/v2/transcript/"+jobID, nil, "", &job); err != nil {
+        return false, Transcript{}, err
+    }
+    switch job.Status {
+    case "completed":
+        return true, job.transcript(), nil
+    case "error":
+        return false, Transcript{}, fmt.Errorf("job failed: %s", job.Error)
+    default:
+        return false, Transcript{}, nil
+    }
+}
+
+// Cancel deletes the job; AssemblyAI has no separate cancel call.
+func (a *assemblyAI) Cancel(ctx context.Context, jobID string) error {
+    return a.do(ctx, http.MethodDelete, "/v2/transcript/"+jobID, nil, "", nil)
+}
+
+// transcript maps utterances (speaker turns) to segments, or falls back to all words as
+// one segment when speaker labels were off.
+func (job aaiJob) transcript() Transcript {
+    t := Transcript{
+        Language: job.LanguageCode,
+        Duration: time.Duration(job.AudioDuration * float64(time.Second)),
+    }
+    for _, u := range job.Utterances {
+        t.Segments = append(t.Segments, Segment{
+            StartSec: float64(u.Start) / 1000,
+            EndSec:   float64(u.End) / 1000,
+            Text:     strings.TrimSpace(u.Text),
+            Speaker:  aaiSpeaker(u.Speaker),
+            Words:    aaiWords(u.Words),
+        })
+    }
+    if len(t.Segments) == 0 {
+        seg := Segment{Text: strings.TrimSpace(job.Text), Words: aaiWords(job.Words)}
+        if n := len(seg.Words); n > 0 {
+            seg.StartSec, seg.EndSec = seg.Words[0].Start, seg.Words[n-1].End
+        }
+        t.Segments = []Segment{seg}
+    }
+    return t
+}
+
+// aaiSpeaker turns AssemblyAI's letter labels (A, B, ...) into "Speaker 1", "Speaker 2", ...
+func aaiSpeaker(label string) string {
+    if label == "" {
+        return ""
+    }
+    if len(label) == 1 && label[0] >= 'A' && label[0] <= 'Z' {
+        return fmt.Sprintf("Speaker %d", int(label[0]-'A')+1)
+    }
+    return "Speaker " + label
+}
+
+func aaiWords(ws []aaiWord) []Word {
+    if len(ws) == 0 {
+        return nil
+    }
+    out := make([]Word, 0, len(ws))
+    for _, w := range ws {
+        out = append(out, Word{Start: float64(w.Start) / 1000, End: float64(w.End) / 1000, Text: w.Text, Probability: w.Confidence})
+    }
+    return out
+}
@@ -1,37 +1,29 @@
 #!/usr/bin/env python3
 import argparse
+import inspect
 import json
 import sys
 from time import perf_counter
 
-def main():
-    p = argparse.ArgumentParser()
-    p.add_argument('--audio', required=True)
-    p.add_argument('--model', default='base.en')
-    p.add_argument('--device', default='auto')  # auto|cpu|cuda
-    args = p.parse_args()
-
-    try:
-        from faster_whisper import WhisperModel
-    except Exception as e:
-        sys.stderr.write('faster-whisper not installed. pip install faster-whisper\n')
-        sys.exit(2)
+def load_model(name, device_arg, compute_arg='auto'):
+    from faster_whisper import WhisperModel
 
     # Choose device and compute_type with safe defaults (avoid None)
-    if args.device == 'cuda':
+    if device_arg == 'cuda':
         device = 'cuda'
         compute_type = 'float16'
-    elif args.device == 'cpu':
+    elif device_arg == 'cpu':
         device = 'cpu'
         compute_type = 'int8'
     else:
         # auto: start with CPU int8, will fallback to CPU anyway if CUDA fails
         device = 'cpu'
         compute_type = 'int8'
+    if compute_arg and compute_arg != 'auto':
+        compute_type = compute_arg
 
-    t0 = perf_counter()
     try:
-        model = WhisperModel(args.model, device=device, compute_type=compute_type or 'default')
+        model = WhisperModel(name, device=device, compute_type=compute_type or 'default')
         device_used = device
     except Exception as e:
         # Fallback to CPU if CUDA/cuDNN missing or broken
@@ -39,24 +31,156 @@ def main():
         if device == 'cuda' and ("cudnn" in msg or "cuda" in msg or "invalid handle" in msg):
             sys.stderr.write('CUDA initialization failed; falling back to CPU (int8).\n')
             device_used = 'cpu'
-            model = WhisperModel(args.model, device='cpu', compute_type='int8')
+            compute_type = 'int8'
+            model = WhisperModel(name, device='cpu', compute_type='int8')
         else:
             raise
-    segments, info = model.transcribe(args.audio)
-    out = {
+    return model, device_used, compute_type
+
+def supports_hotwords():
+    from faster_whisper import WhisperModel
+    try:
+        return 'hotwords' in inspect.signature(WhisperModel.transcribe).parameters
+    except (TypeError, ValueError):
+        return False
+
+def decode_options(req):
+    # Only pass what the caller set so faster-whisper's own defaults apply otherwise.
+    opts = {}
+    if req.get('language'):
+        opts['language'] = req['language']
+    if req.get('task'):
+        opts['task'] = req['task']
+    if req.get('beam_size'):
+        opts['beam_size'] = int(req['beam_size'])
+    if req.get('vad_filter'):
+        opts['vad_filter'] = True
+    if req.get('initial_prompt'):
+        opts['initial_prompt'] = req['initial_prompt']
+    if req.get('hotwords'):
+        if supports_hotwords():
+            opts['hotwords'] = req['hotwords']
+        elif req.get('hotwords_prompt'):
+            # older faster-whisper: use the prompt with the terms folded in, which the
+            # caller has already trimmed to the prompt budget
+            opts['initial_prompt'] = req['hotwords_prompt']
+    if req.get('word_timestamps'):
+        opts['word_timestamps'] = True
+    return opts
+
+def seg_dict(s):
+    d = {'start': float(s.start), 'end': float(s.end), 'text': s.text.strip()}
+    words = getattr(s, 'words', None)
+    if words:
+        d['words'] = [
+            {
+                'start': float(w.start),
+                'end': float(w.end),
+                'word': w.word.strip(),
+                'probability': float(getattr(w, 'probability', 0.0)),
+            }
+            for w in words
+        ]
+    return d
+
+def transcribe(model, device_used, audio, opts):
+    segments, info = model.transcribe(audio, **opts)
+    return {
         'language': getattr(info, 'language', ''),
         'duration': getattr(info, 'duration', 0.0),
         'device_used': device_used,
-        'segments': [
-            {
-                'start': float(s.start),
-                'end': float(s.end),
-                'text': s.text.strip(),
-            }
-            for s in segments
-        ],
+        'segments': [seg_dict(s) for s in segments],
     }
-    sys.stdout.write(json.dumps(out))
+
+def transcribe_streaming(model, device_used, audio, opts, rid):
+    # faster-whisper decodes lazily, so each segment is sent as soon as it is produced:
+    # an "info" event first (language, total duration), one "segment" event per segment,
+    # then the final result without the segments.
+    segments, info = model.transcribe(audio, **opts)
+    duration = getattr(info, 'duration', 0.0)
+    language = getattr(info, 'language', '')
+    reply({'id': rid, 'event': 'info', 'info': {'language': language, 'duration': duration}})
+    for s in segments:
+        reply({'id': rid, 'event': 'segment', 'segment': seg_dict(s)})
+    return {'language': language, 'duration': duration, 'device_used': device_used}
+
+def reply(obj):
+    sys.stdout.write(json.dumps(obj) + '\n')
+    sys.stdout.flush()
+
+def serve(model, device_used, compute_type):
+    # Line-delimited JSON over stdin/stdout. One request per line:
+    #   {"id": 1, "op": "transcribe", "audio": "/path.wav", "language": "en", "task": "translate", "beam_size": 5,
+    #    "vad_filter": true, "initial_prompt": "...", "hotwords": "...", "hotwords_prompt": "...", "word_timestamps": true}
+    #   {"id": 2, "op": "ping"}
+    # Each request ends with exactly one response line carrying the same id, with either
+    # "result" or "error"; transcribe streams "event" lines before it. EOF on stdin shuts
+    # the worker down.
+    reply({'ready': True, 'device_used': device_used, 'compute_type': compute_type})
+    for line in sys.stdin:
+        line = line.strip()
+        if not line:
+            continue
+        try:
+            req = json.loads(line)
+        except Exception as e:
+            reply({'id': 0, 'error': 'bad request: %s' % e})
+            continue
+        rid = req.get('id', 0)
+        op = req.get('op', 'transcribe')
+        try:
+            if op == 'ping':
+                reply({'id': rid, 'result': 'pong'})
+            elif op == 'transcribe':
+                t0 = perf_counter()
+                out = transcribe_streaming(model, device_used, req['audio'], decode_options(req), rid)
+                out['elapsed'] = perf_counter() - t0
+                reply({'id': rid, 'result': out})
+            else:
+                reply({'id': rid, 'error': 'unknown op: %s' % op})
+        except Exception as e:
+            reply({'id': rid, 'error': str(e)})
+
+def main():
+    p = argparse.ArgumentParser()
+    p.add_argument('--audio')
+    p.add_argument('--model', default='base.en')
+    p.add_argument('--device', default='auto')  # auto|cpu|cuda
+    p.add_argument('--compute-type', default='auto')  # auto|int8|int8_float16|float16|float32
+    p.add_argument('--language')
+    p.add_argument('--task', choices=['transcribe', 'translate'])
+    p.add_argument('--beam-size', type=int)
+    p.add_argument('--vad-filter', action='store_true')
+    p.add_argument('--initial-prompt')
+    p.add_argument('--hotwords')
+    p.add_argument('--hotwords-prompt')
+    p.add_argument('--word-timestamps', action='store_true')
+    p.add_argument('--serve', action='store_true', help='keep the model loaded and answer JSON line requests on stdin')
+    args = p.parse_args()
+    if not args.serve and not args.audio:
+        p.error('--audio is required unless --serve is given')
+
+    try:
+        import faster_whisper  # noqa: F401
+    except Exception as e:
+        sys.stderr.write('faster-whisper not installed. pip install faster-whisper\n')
+        sys.exit(2)
+
+    model, device_used, compute_type = load_model(args.model, args.device, args.compute_type)
+    if args.serve:
+        serve(model, device_used, compute_type)
+        return
+    opts = decode_options({
+        'language': args.language,
+        'task': args.task,
+        'beam_size': args.beam_size,
+        'vad_filter': args.vad_filter,
+        'initial_prompt': args.initial_prompt,
+        'hotwords': args.hotwords,
+        'hotwords_prompt': args.hotwords_prompt,
+        'word_timestamps': args.word_timestamps,
+    })
+    sys.stdout.write(json.dumps(transcribe(model, device_used, args.audio, opts)))
 
 if __name__ == '__main__':
     main()
@@ -0,0 +1,100 @@
+package transcribe
+
+import (
+    "context"
+    "fmt"
+    "time"
+)
+
+// AsyncProvider is a service that transcribes through an upload → create job → poll
+// workflow (AssemblyAI, Rev, Speechmatics, ...) rather than a single synchronous request.
+type AsyncProvider interface {
+    // Upload sends the audio and returns a reference the job can point at (URL or ID).
+    Upload(ctx context.Context, audioPath string) (string, error)
+    // Submit creates a transcription job for the uploaded audio and returns its ID.
+    Submit(ctx context.Context, audioRef string) (string, error)
+    // Poll checks a job. It returns done=false while the job is still running.
+    Poll(ctx context.Context, jobID string) (done bool, tr Transcript, err error)
+    // Cancel stops or discards a job; it is best effort and called with a fresh context.
+    Cancel(ctx context.Context, jobID string) error
+}
+
+// AsyncOptions controls how often jobs are polled.
+type AsyncOptions struct {
+    PollInterval    time.Duration // first wait between polls
+    MaxPollInterval time.Duration // polls back off by 1.5x up to this
+}
+
+// DefaultAsyncOptions polls after 3s, backing off to every 30s for long jobs.
+var DefaultAsyncOptions = AsyncOptions{PollInterval: 3 * time.Second, MaxPollInterval: 30 * time.Second}
+
+// asyncBackend adapts an AsyncProvider to the Backend interface.
+type asyncBackend struct {
+    name     string
+    provider AsyncProvider
+    opts     AsyncOptions
+}
+
+// NewAsyncBackend drives p through upload, submit and polling. If ctx ends while the job
+// is running, the job is cancelled so it does not keep running (and billing) remotely.
+func NewAsyncBackend(name string, p AsyncProvider, opts AsyncOptions) Backend {
+    if opts.PollInterval <= 0 {
+        opts.PollInterval = DefaultAsyncOptions.PollInterval
+    }
+    if opts.MaxPollInterval < opts.PollInterval {
+        opts.MaxPollInterval = opts.PollInterval
+    }
+    return &asyncBackend{name: name, provider: p, opts: opts}
+}
+
+func (a *asyncBackend) Fingerprint() string {
+    if fp, ok := a.provider.(Fingerprinter); ok {
+        return fp.Fingerprint()
+    }
+    return a.name
+}
+
+func (a *asyncBackend) Transcribe(ctx context.Context, audioPath string) (Transcript, error) {
+    ref, err := a.provider.Upload(ctx, audioPath)
+    if err != nil {
+        return Transcript{}, fmt.Errorf("%s upload: %w", a.name, err)
+    }
+    jobID, err := a.provider.Submit(ctx, ref)
+    if err != nil {
+        return Transcript{}, fmt.Errorf("%s submit: %w", a.name, err)
+    }
+
+    wait := a.opts.PollInterval
+    for {
+        t := time.NewTimer(wait)
+        select {
+        case <-ctx.Done():
+            t.Stop()
+            a.cancel(ctx, jobID)
+            return Transcript{}, ctx.Err()
+        case <-t.C:
+        }
+        done, tr, err := a.provider.Poll(ctx, jobID)
+        if err != nil {
+            if ctx.Err() != nil {
+                a.cancel(ctx, jobID)
+                return Transcript{}, ctx.Err()
+            }
+            return Transcript{}, fmt.Errorf("%s job %s: %w", a.name, jobID, err)
+        }
+        if done {
+            return tr, nil
+        }
+        wait = time.Duration(float64(wait) * 1.5)
+        if wait > a.opts.MaxPollInterval {
+            wait = a.opts.MaxPollInterval
+        }
+    }
+}
+
+// cancel tells the provider to drop the job, outliving the cancelled parent context briefly.
+func (a *asyncBackend) cancel(parent context.Context, jobID string) {
+    ctx, cancel := context.WithTimeout(context.WithoutCancel(parent), 10*time.Second)
+    defer cancel()
+    a.provider.Cancel(ctx, jobID)
+}
@@ -11,13 +11,32 @@ type Segment struct {
     EndSec   float64
     Text     string
     Speaker  string // optional; to be filled by diarization
+    Words    []Word // optional; only when the backend reports word timings
 }
 
+// Word is a single timed word within a segment.
+type Word struct {
+    Start       float64
+    End         float64
+    Text        string
+    Probability float64 // 0 when the backend does not report confidence
+}
+
+// Tasks understood by backends that can translate as well as transcribe.
+const (
+    TaskTranscribe = "transcribe"
+    TaskTranslate  = "translate" // speech in any language to English text
+)
+
 // Transcript bundles the segments.
 type Transcript struct {
-    Language string
-    Segments []Segment
-    Duration time.Duration
+    Language       string // spoken (source) language, when the backend detects or was told it
+    OutputLanguage string // language of the text; empty means the same as Language
+    Segments       []Segment
+    Duration       time.Duration
+    Device         string // optional; hardware a local backend ran on (cpu|cuda)
+    ComputeType    string // optional; numeric precision of a local model (int8, float16, ...)
+    Backend        string // optional; which member of a fallback chain produced the transcript
 }
 
 // Backend is a pluggable transcription backend.
@@ -25,3 +44,9 @@ type Backend interface {
     Transcribe(ctx context.Context, audioPath string) (Transcript, error)
 }
 
+// Fingerprinter is implemented by backends that can describe every setting affecting
+// their output (model, endpoint, decoding options; never credentials). Used as part of
+// the transcript cache key.
+type Fingerprinter interface {
+    Fingerprint() string
+}
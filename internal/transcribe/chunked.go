@@ -0,0 +1,231 @@
+package transcribe
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "os"
+    "strings"
+    "sync"
+    "time"
+    "unicode"
+
+    "github.com/zudsniper/meet-recording-processor/internal/media"
+)
+
+// UploadLimiter is implemented by backends that reject audio files above a size limit.
+type UploadLimiter interface {
+    MaxUploadBytes() int64
+}
+
+// ChunkOptions controls how audio is split before being sent to a size-limited backend.
+type ChunkOptions struct {
+    MaxDuration time.Duration // 0 derives the limit from the backend's MaxUploadBytes
+    Overlap     time.Duration // audio repeated across chunk boundaries
+    Concurrency int           // chunks transcribed at once; values below 1 mean sequential
+    TmpDir      string
+}
+
+// chunkedBackend splits long audio into pieces the inner backend accepts and stitches
+// the results back into a single transcript with global timestamps.
+type chunkedBackend struct {
+    inner Backend
+    opts  ChunkOptions
+}
+
+// NewChunkedBackend wraps inner so that audio exceeding its upload limit (or opts.MaxDuration)
+// is transcribed in chunks. Audio that already fits is passed through untouched.
+func NewChunkedBackend(inner Backend, opts ChunkOptions) Backend {
+    return &chunkedBackend{inner: inner, opts: opts}
+}
+
+// Fingerprint extends the inner backend's fingerprint with the chunking settings, which
+// change where cuts land and therefore the output.
+func (c *chunkedBackend) Fingerprint() string {
+    inner := fmt.Sprintf("%T", c.inner)
+    if fp, ok := c.inner.(Fingerprinter); ok {
+        inner = fp.Fingerprint()
+    }
+    return fmt.Sprintf("%s chunk-max=%s overlap=%s", inner, c.opts.MaxDuration, c.opts.Overlap)
+}
+
+func (c *chunkedBackend) Transcribe(ctx context.Context, audioPath string) (Transcript, error) {
+    maxSec, err := c.maxChunkSec(audioPath)
+    if err != nil {
+        return Transcript{}, err
+    }
+    if maxSec <= 0 {
+        return c.inner.Transcribe(ctx, audioPath)
+    }
+    chunks, err := media.SplitWAV(ctx, audioPath, c.opts.TmpDir, media.SplitOptions{
+        MaxDurationSec: maxSec,
+        OverlapSec:     c.opts.Overlap.Seconds(),
+    })
+    if err != nil {
+        return Transcript{}, fmt.Errorf("split audio: %w", err)
+    }
+    if len(chunks) == 1 {
+        return c.inner.Transcribe(ctx, audioPath)
+    }
+    defer func() {
+        for _, ch := range chunks {
+            if ch.Path != audioPath { os.Remove(ch.Path) }
+        }
+    }()
+
+    parts, err := c.transcribeChunks(ctx, chunks)
+    if err != nil {
+        return Transcript{}, err
+    }
+    return stitch(chunks, parts), nil
+}
+
+// transcribeChunks runs up to opts.Concurrency inner transcriptions at once and returns the
+// results in chunk order. The first failure cancels the remaining work; every chunk that
+// failed on its own (rather than from that cancellation) is reported with its time range.
+func (c *chunkedBackend) transcribeChunks(ctx context.Context, chunks []media.Chunk) ([]Transcript, error) {
+    limit := c.opts.Concurrency
+    if limit < 1 { limit = 1 }
+    if limit > len(chunks) { limit = len(chunks) }
+
+    ctx, cancel := context.WithCancel(ctx)
+    defer cancel()
+
+    parts := make([]Transcript, len(chunks))
+    errs := make([]error, len(chunks))
+    sem := make(chan struct{}, limit)
+    var wg sync.WaitGroup
+    for i := range chunks {
+        select {
+        case sem <- struct{}{}:
+        case <-ctx.Done():
+        }
+        if ctx.Err() != nil {
+            break
+        }
+        wg.Add(1)
+        go func(i int) {
+            defer wg.Done()
+            defer func() { <-sem }()
+            tr, err := c.inner.Transcribe(ctx, chunks[i].Path)
+            if err != nil {
+                errs[i] = err
+                cancel()
+                return
+            }
+            parts[i] = tr
+        }(i)
+    }
+    wg.Wait()
+
+    var failed []error
+    for i, err := range errs {
+        if err == nil || errors.Is(err, context.Canceled) {
+            continue
+        }
+        ch := chunks[i]
+        failed = append(failed, fmt.Errorf("chunk %d/%d [%s-%s]: %w", i+1, len(chunks), fmtSec(ch.StartSec), fmtSec(ch.EndSec), err))
+    }
+    if len(failed) > 0 {
+        return nil, errors.Join(failed...)
+    }
+    // nothing failed on its own, so any cancellation came from the caller
+    if err := ctx.Err(); err != nil {
+        return nil, err
+    }
+    return parts, nil
+}
+
+// maxChunkSec returns the longest chunk the backend accepts for this file, or 0 when no
+// splitting is needed.
+func (c *chunkedBackend) maxChunkSec(audioPath string) (float64, error) {
+    info, err := media.ReadWAVInfo(audioPath)
+    if err != nil {
+        return 0, fmt.Errorf("inspect audio: %w", err)
+    }
+    maxSec := c.opts.MaxDuration.Seconds()
+    if lim, ok := c.inner.(UploadLimiter); ok && lim.MaxUploadBytes() > 0 && info.ByteRate() > 0 {
+        // leave headroom for the WAV header and multipart framing
+        bySize := float64(lim.MaxUploadBytes()-64*1024) / float64(info.ByteRate())
+        if maxSec <= 0 || bySize < maxSec {
+            maxSec = bySize
+        }
+    }
+    if maxSec <= 0 || info.DurationSec() <= maxSec {
+        return 0, nil
+    }
+    return maxSec, nil
+}
+
+// stitch merges per-chunk transcripts, shifting timestamps to the source timeline and
+// dropping segments that belong to the overlap already covered by the previous chunk.
+// Words repeated across the boundary are only looked for in the first segment kept from
+// each chunk; later repeats ("Sounds good." "Good.") are real speech.
+func stitch(chunks []media.Chunk, parts []Transcript) Transcript {
+    var out Transcript
+    for i, tr := range parts {
+        ch := chunks[i]
+        boundary := i > 0
+        if out.Language == "" { out.Language = tr.Language }
+        if out.OutputLanguage == "" { out.OutputLanguage = tr.OutputLanguage }
+        for _, s := range tr.Segments {
+            if s.EndSec <= 0 {
+                // untimed backends: attribute the text to the chunk's own span
+                s.StartSec, s.EndSec = ch.KeepSec, ch.EndSec
+            } else {
+                s.StartSec += ch.StartSec
+                s.EndSec += ch.StartSec
+                s.Words = shiftWords(s.Words, ch.StartSec)
+                if i > 0 && (s.StartSec+s.EndSec)/2 < ch.KeepSec {
+                    continue
+                }
+            }
+            if n := len(out.Segments); n > 0 && boundary {
+                boundary = false
+                trimmed := trimRepeatedWords(out.Segments[n-1].Text, s.Text)
+                if trimmed == "" { continue }
+                // keep word timings in step with the text when both were cut the same way
+                if cut := len(strings.Fields(s.Text)) - len(strings.Fields(trimmed)); cut > 0 && len(s.Words) == len(strings.Fields(s.Text)) {
+                    s.Words = s.Words[cut:]
+                    s.StartSec = s.Words[0].Start
+                }
+                s.Text = trimmed
+            }
+            out.Segments = append(out.Segments, s)
+        }
+    }
+    if last := chunks[len(chunks)-1]; last.EndSec > 0 {
+        out.Duration = time.Duration(last.EndSec * float64(time.Second))
+    }
+    return out
+}
+
+// trimRepeatedWords removes the longest run of words at the start of next that repeats
+// the end of prev, which is how overlap shows up in untimed or loosely timed output.
+func trimRepeatedWords(prev, next string) string {
+    pw, nw := strings.Fields(prev), strings.Fields(next)
+    best := 0
+    for k := 1; k <= len(pw) && k <= len(nw); k++ {
+        match := true
+        for j := 0; j < k; j++ {
+            if normWord(pw[len(pw)-k+j]) != normWord(nw[j]) {
+                match = false
+                break
+            }
+        }
+        if match { best = k }
+    }
+    // a single word ("the", "okay") is more likely coincidence than overlap
+    if best < 2 {
+        return next
+    }
+    return strings.Join(nw[best:], " ")
+}
+
+func normWord(w string) string {
+    return strings.ToLower(strings.TrimFunc(w, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsNumber(r) }))
+}
+
+func fmtSec(sec float64) string {
+    return (time.Duration(sec) * time.Second).String()
+}
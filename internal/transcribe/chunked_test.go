@@ -0,0 +1,67 @@
+package transcribe
+
+import (
+    "testing"
+
+    "github.com/zudsniper/meet-recording-processor/internal/media"
+)
+
+func segmentTexts(tr Transcript) []string {
+    out := make([]string, len(tr.Segments))
+    for i, s := range tr.Segments {
+        out[i] = s.Text
+    }
+    return out
+}
+
+func TestStitchTrimsOverlapAtBoundary(t *testing.T) {
+    chunks := []media.Chunk{
+        {StartSec: 0, EndSec: 600},
+        {StartSec: 595, KeepSec: 600, EndSec: 1200},
+    }
+    parts := []Transcript{
+        {Segments: []Segment{
+            {StartSec: 590, EndSec: 599, Text: "We should ship it on Friday then."},
+        }},
+        {Segments: []Segment{
+            {StartSec: 4, EndSec: 8, Text: "on Friday then. Any objections?"},
+            {StartSec: 9, EndSec: 10, Text: "None."},
+        }},
+    }
+    got := segmentTexts(stitch(chunks, parts))
+    want := []string{"We should ship it on Friday then.", "Any objections?", "None."}
+    if len(got) != len(want) {
+        t.Fatalf("got %q, want %q", got, want)
+    }
+    for i := range want {
+        if got[i] != want[i] {
+            t.Errorf("segment %d: got %q, want %q", i, got[i], want[i])
+        }
+    }
+}
+
+func TestStitchKeepsRealRepeats(t *testing.T) {
+    chunks := []media.Chunk{
+        {StartSec: 0, EndSec: 600},
+        {StartSec: 595, KeepSec: 600, EndSec: 1200},
+    }
+    parts := []Transcript{
+        {Segments: []Segment{{StartSec: 10, EndSec: 20, Text: "Let's move on."}}},
+        {Segments: []Segment{
+            {StartSec: 10, EndSec: 12, Text: "Does that work? Sounds good."},
+            {StartSec: 12, EndSec: 13, Text: "Good."},
+            {StartSec: 14, EndSec: 17, Text: "Thank you so much, thank you."},
+            {StartSec: 17, EndSec: 18, Text: "Thank you."},
+        }},
+    }
+    got := segmentTexts(stitch(chunks, parts))
+    want := []string{"Let's move on.", "Does that work? Sounds good.", "Good.", "Thank you so much, thank you.", "Thank you."}
+    if len(got) != len(want) {
+        t.Fatalf("got %q, want %q", got, want)
+    }
+    for i := range want {
+        if got[i] != want[i] {
+            t.Errorf("segment %d: got %q, want %q", i, got[i], want[i])
+        }
+    }
+}
@@ -3,6 +3,7 @@ package transcribe
 import (
     "bytes"
     "context"
+    "encoding/base64"
     "encoding/json"
     "fmt"
     "io"
@@ -10,20 +11,54 @@ import (
     "net/http"
     "os"
     "path/filepath"
+    "strings"
     "time"
 )
 
+// CloudflareOptions configures the Workers AI backend. Task, Language, VADFilter and
+// InitialPrompt are only understood by models taking a JSON body (see usesJSONBody).
+type CloudflareOptions struct {
+    AccountID     string
+    APIToken      string
+    Model         string
+    Task          string // transcribe|translate
+    Language      string
+    VADFilter     bool
+    InitialPrompt string
+    Retry         RetryPolicy
+}
+
 // Cloudflare Workers AI backend.
 // POST https://api.cloudflare.com/client/v4/accounts/{account_id}/ai/run/{model}
 // With bearer API token.
 type cloudflareBackend struct {
-    accountID string
-    apiToken  string
-    model     string
+    opts CloudflareOptions
+}
+
+func NewCloudflareBackend(opts CloudflareOptions) Backend {
+    return &cloudflareBackend{opts: opts}
+}
+
+func (c *cloudflareBackend) Fingerprint() string {
+    return fmt.Sprintf("cloudflare model=%s task=%s language=%s vad=%t prompt=%q",
+        c.opts.Model, c.opts.Task, c.opts.Language, c.opts.VADFilter, c.opts.InitialPrompt)
 }
 
-func NewCloudflareBackend(accountID, apiToken, model string) Backend {
-    return &cloudflareBackend{accountID: accountID, apiToken: apiToken, model: model}
+// usesJSONBody reports whether the model expects {"audio": "<base64>", ...} rather than
+// a multipart upload. The older whisper and whisper-tiny-en models take raw audio;
+// whisper-large-v3-turbo and later take JSON with decoding options.
+func (c *cloudflareBackend) usesJSONBody() bool {
+    return strings.Contains(strings.ToLower(c.opts.Model), "whisper-large-v3")
+}
+
+// MaxUploadBytes keeps requests well under the Workers AI body limit; whisper also
+// degrades noticeably on very long inputs, so smaller pieces transcribe better.
+// Base64 encoding grows the audio by a third, so JSON models get less raw audio.
+func (c *cloudflareBackend) MaxUploadBytes() int64 {
+    if c.usesJSONBody() {
+        return 15 * 1024 * 1024
+    }
+    return 20 * 1024 * 1024
 }
 
 type cfResp struct {
@@ -32,62 +67,169 @@ type cfResp struct {
     Result  json.RawMessage `json:"result"`
 }
 
+type cfWord struct {
+    Word  string  `json:"word"`
+    Start float64 `json:"start"`
+    End   float64 `json:"end"`
+}
+
+// cfWhisperResult is the union of the whisper model result shapes:
+// @cf/openai/whisper and whisper-tiny-en return text, words and vtt;
+// whisper-large-v3-turbo adds transcription_info and timestamped segments.
 type cfWhisperResult struct {
-    Text string `json:"text"`
+    Text              string   `json:"text"`
+    Words             []cfWord `json:"words"`
+    VTT               string   `json:"vtt"`
+    TranscriptionInfo struct {
+        Language string  `json:"language"`
+        Duration float64 `json:"duration"`
+    } `json:"transcription_info"`
+    Segments []struct {
+        Start float64  `json:"start"`
+        End   float64  `json:"end"`
+        Text  string   `json:"text"`
+        Words []cfWord `json:"words"`
+    } `json:"segments"`
 }
 
 func (c *cloudflareBackend) Transcribe(ctx context.Context, audioPath string) (Transcript, error) {
+    if c.opts.Task == TaskTranslate && !c.usesJSONBody() {
+        return Transcript{}, fmt.Errorf("cloudflare model %s cannot translate; use @cf/openai/whisper-large-v3-turbo", c.opts.Model)
+    }
     f, err := os.Open(audioPath)
     if err != nil {
         return Transcript{}, err
     }
     defer f.Close()
 
-    var body bytes.Buffer
-    mw := multipart.NewWriter(&body)
-    fw, err := mw.CreateFormFile("file", filepath.Base(audioPath))
-    if err != nil {
-        return Transcript{}, err
-    }
-    if _, err := io.Copy(fw, f); err != nil {
-        return Transcript{}, err
+    var payload []byte
+    var contentType string
+    if c.usesJSONBody() {
+        payload, err = c.jsonBody(f)
+        contentType = "application/json"
+    } else {
+        payload, contentType, err = multipartBody(f, filepath.Base(audioPath))
     }
-    if err := mw.Close(); err != nil {
-        return Transcript{}, err
-    }
-
-    url := fmt.Sprintf("https://api.cloudflare.com/client/v4/accounts/%s/ai/run/%s", c.accountID, c.model)
-    req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, &body)
     if err != nil {
         return Transcript{}, err
     }
-    req.Header.Set("Authorization", "Bearer "+c.apiToken)
-    req.Header.Set("Content-Type", mw.FormDataContentType())
 
+    url := fmt.Sprintf("https://api.cloudflare.com/client/v4/accounts/%s/ai/run/%s", c.opts.AccountID, c.opts.Model)
     hc := &http.Client{Timeout: 60 * time.Minute}
-    resp, err := hc.Do(req)
+    resp, err := doWithRetry(ctx, hc, c.opts.Retry, "cloudflare", func() (*http.Request, error) {
+        req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+        if err != nil {
+            return nil, err
+        }
+        req.Header.Set("Authorization", "Bearer "+c.opts.APIToken)
+        req.Header.Set("Content-Type", contentType)
+        return req, nil
+    })
     if err != nil {
         return Transcript{}, err
     }
     defer resp.Body.Close()
-    if resp.StatusCode >= 300 {
-        b, _ := io.ReadAll(resp.Body)
-        return Transcript{}, fmt.Errorf("cloudflare http %d: %s", resp.StatusCode, string(b))
-    }
     var cr cfResp
     if err := json.NewDecoder(resp.Body).Decode(&cr); err != nil {
         return Transcript{}, err
     }
     if !cr.Success {
-        return Transcript{}, fmt.Errorf("cloudflare response not successful: %s", string(cr.Result))
+        errs, _ := json.Marshal(cr.Errors)
+        return Transcript{}, fmt.Errorf("cloudflare response not successful: %s", string(errs))
+    }
+    tr := parseCloudflareResult(cr.Result)
+    if c.opts.Task == TaskTranslate {
+        tr.OutputLanguage = "en"
     }
+    return tr, nil
+}
+
+// multipartBody wraps the audio as the "file" field of a multipart form.
+func multipartBody(r io.Reader, name string) ([]byte, string, error) {
+    var body bytes.Buffer
+    mw := multipart.NewWriter(&body)
+    fw, err := mw.CreateFormFile("file", name)
+    if err != nil {
+        return nil, "", err
+    }
+    if _, err := io.Copy(fw, r); err != nil {
+        return nil, "", err
+    }
+    if err := mw.Close(); err != nil {
+        return nil, "", err
+    }
+    return body.Bytes(), mw.FormDataContentType(), nil
+}
+
+// cfJSONRequest is the input schema of the JSON-bodied whisper models.
+type cfJSONRequest struct {
+    Audio         string `json:"audio"`
+    Task          string `json:"task,omitempty"`
+    Language      string `json:"language,omitempty"`
+    VADFilter     bool   `json:"vad_filter,omitempty"`
+    InitialPrompt string `json:"initial_prompt,omitempty"`
+}
+
+// jsonBody encodes the audio as base64 alongside the decoding options.
+func (c *cloudflareBackend) jsonBody(r io.Reader) ([]byte, error) {
+    audio, err := io.ReadAll(r)
+    if err != nil {
+        return nil, err
+    }
+    return json.Marshal(cfJSONRequest{
+        Audio:         base64.StdEncoding.EncodeToString(audio),
+        Task:          c.opts.Task,
+        Language:      c.opts.Language,
+        VADFilter:     c.opts.VADFilter,
+        InitialPrompt: c.opts.InitialPrompt,
+    })
+}
+
+// parseCloudflareResult builds a transcript from the richest data the model returned:
+// segments, then VTT cues, then the word list, then the bare text. Results that do not
+// decode as a whisper object at all are kept verbatim as a single untimed segment.
+func parseCloudflareResult(raw json.RawMessage) Transcript {
     var wr cfWhisperResult
-    if err := json.Unmarshal(cr.Result, &wr); err != nil {
-        // Some models might return different formats; fallback to raw string
-        // Attempt to decode as { "text": "..." } first; otherwise make a best effort
-        return Transcript{}, fmt.Errorf("cloudflare unexpected result: %w", err)
+    if err := json.Unmarshal(raw, &wr); err != nil {
+        var text string
+        if json.Unmarshal(raw, &text) != nil {
+            text = string(raw)
+        }
+        return Transcript{Segments: []Segment{{Text: strings.TrimSpace(text)}}}
+    }
+
+    t := Transcript{
+        Language: wr.TranscriptionInfo.Language,
+        Duration: time.Duration(wr.TranscriptionInfo.Duration * float64(time.Second)),
+    }
+    words := make([]Word, 0, len(wr.Words))
+    for _, w := range wr.Words {
+        words = append(words, Word{Start: w.Start, End: w.End, Text: strings.TrimSpace(w.Word)})
+    }
+
+    switch {
+    case len(wr.Segments) > 0:
+        for _, s := range wr.Segments {
+            seg := Segment{StartSec: s.Start, EndSec: s.End, Text: strings.TrimSpace(s.Text)}
+            for _, w := range s.Words {
+                seg.Words = append(seg.Words, Word{Start: w.Start, End: w.End, Text: strings.TrimSpace(w.Word)})
+            }
+            t.Segments = append(t.Segments, seg)
+        }
+    case strings.TrimSpace(wr.VTT) != "":
+        t.Segments = parseVTT(wr.VTT)
+        attachWords(t.Segments, words)
+    case len(words) > 0:
+        t.Segments = []Segment{{StartSec: words[0].Start, EndSec: words[len(words)-1].End, Text: strings.TrimSpace(wr.Text), Words: words}}
+    }
+    if len(t.Segments) == 0 {
+        t.Segments = []Segment{{Text: strings.TrimSpace(wr.Text)}}
+    }
+    if t.Duration == 0 {
+        if end := t.Segments[len(t.Segments)-1].EndSec; end > 0 {
+            t.Duration = time.Duration(end * float64(time.Second))
+        }
     }
-    t := Transcript{Language: "", Segments: []Segment{{StartSec: 0, EndSec: 0, Text: wr.Text}}, Duration: 0}
-    return t, nil
+    return t
 }
 
@@ -0,0 +1,187 @@
+package transcribe
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "net/url"
+    "os"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// DefaultDeepgramBaseURL is the API root used when DeepgramOptions.BaseURL is empty.
+const DefaultDeepgramBaseURL = "https://api.deepgram.com"
+
+// DeepgramOptions configures the Deepgram prerecorded backend.
+type DeepgramOptions struct {
+    APIKey      string
+    BaseURL     string // API root; point at a stand-in server for testing
+    Model       string // e.g. nova-3
+    Language    string // empty lets Deepgram use its default
+    Diarize     bool   // label utterances by speaker
+    SmartFormat bool   // punctuation, numerals, dates
+    Utterances  bool   // segment the transcript into utterances
+    Retry       RetryPolicy
+}
+
+// Deepgram prerecorded transcription via POST {base}/v1/listen with the WAV as the body.
+type deepgramBackend struct {
+    opts DeepgramOptions
+}
+
+func NewDeepgramBackend(opts DeepgramOptions) Backend {
+    if opts.BaseURL == "" {
+        opts.BaseURL = DefaultDeepgramBaseURL
+    }
+    return &deepgramBackend{opts: opts}
+}
+
+func (d *deepgramBackend) Fingerprint() string {
+    o := d.opts
+    return fmt.Sprintf("deepgram base=%s model=%s language=%s diarize=%t smart_format=%t utterances=%t",
+        o.BaseURL, o.Model, o.Language, o.Diarize, o.SmartFormat, o.Utterances)
+}
+
+type dgWord struct {
+    Word           string  `json:"word"`
+    PunctuatedWord string  `json:"punctuated_word"`
+    Start          float64 `json:"start"`
+    End            float64 `json:"end"`
+    Confidence     float64 `json:"confidence"`
+    Speaker        *int    `json:"speaker"`
+}
+
+type dgResp struct {
+    Metadata struct {
+        Duration float64 `json:"duration"`
+    } `json:"metadata"`
+    Results struct {
+        Channels []struct {
+            DetectedLanguage string `json:"detected_language"`
+            Alternatives     []struct {
+                Transcript string   `json:"transcript"`
+                Words      []dgWord `json:"words"`
+            } `json:"alternatives"`
+        } `json:"channels"`
+        Utterances []struct {
+            Start      float64  `json:"start"`
+            End        float64  `json:"end"`
+            Transcript string   `json:"transcript"`
+            Speaker    *int     `json:"speaker"`
+            Words      []dgWord `json:"words"`
+        } `json:"utterances"`
+    } `json:"results"`
+}
+
+func (d *deepgramBackend) endpoint() (string, error) {
+    u, err := url.Parse(strings.TrimSuffix(d.opts.BaseURL, "/") + "/v1/listen")
+    if err != nil {
+        return "", fmt.Errorf("invalid deepgram base url %q: %w", d.opts.BaseURL, err)
+    }
+    q := u.Query()
+    if d.opts.Model != "" {
+        q.Set("model", d.opts.Model)
+    }
+    if d.opts.Language != "" {
+        q.Set("language", d.opts.Language)
+    }
+    q.Set("diarize", strconv.FormatBool(d.opts.Diarize))
+    q.Set("smart_format", strconv.FormatBool(d.opts.SmartFormat))
+    q.Set("utterances", strconv.FormatBool(d.opts.Utterances))
+    q.Set("punctuate", "true")
+    u.RawQuery = q.Encode()
+    return u.String(), nil
+}
+
+func (d *deepgramBackend) Transcribe(ctx context.Context, audioPath string) (Transcript, error) {
+    audio, err := os.ReadFile(audioPath)
+    if err != nil {
+        return Transcript{}, err
+    }
+    endpoint, err := d.endpoint()
+    if err != nil {
+        return Transcript{}, err
+    }
+
+    hc := &http.Client{Timeout: 60 * time.Minute}
+    resp, err := doWithRetry(ctx, hc, d.opts.Retry, "deepgram", func() (*http.Request, error) {
+        req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(audio))
+        if err != nil {
+            return nil, err
+        }
+        req.Header.Set("Authorization", "Token "+d.opts.APIKey)
+        req.Header.Set("Content-Type", "audio/wav")
+        return req, nil
+    })
+    if err != nil {
+        return Transcript{}, err
+    }
+    defer resp.Body.Close()
+    var dr dgResp
+    if err := json.NewDecoder(resp.Body).Decode(&dr); err != nil {
+        return Transcript{}, fmt.Errorf("deepgram response: %w", err)
+    }
+    return dr.transcript(), nil
+}
+
+// transcript maps utterances to segments (with speakers when diarized), falling back to the
+// first channel's transcript as one segment when utterances were not requested.
+func (dr dgResp) transcript() Transcript {
+    t := Transcript{Duration: time.Duration(dr.Metadata.Duration * float64(time.Second))}
+    var alt struct {
+        Transcript string
+        Words      []dgWord
+    }
+    if len(dr.Results.Channels) > 0 {
+        ch := dr.Results.Channels[0]
+        t.Language = ch.DetectedLanguage
+        if len(ch.Alternatives) > 0 {
+            alt.Transcript, alt.Words = ch.Alternatives[0].Transcript, ch.Alternatives[0].Words
+        }
+    }
+
+    for _, u := range dr.Results.Utterances {
+        t.Segments = append(t.Segments, Segment{
+            StartSec: u.Start,
+            EndSec:   u.End,
+            Text:     strings.TrimSpace(u.Transcript),
+            Speaker:  dgSpeaker(u.Speaker),
+            Words:    dgWords(u.Words),
+        })
+    }
+    if len(t.Segments) == 0 && strings.TrimSpace(alt.Transcript) != "" {
+        seg := Segment{Text: strings.TrimSpace(alt.Transcript), Words: dgWords(alt.Words)}
+        if n := len(seg.Words); n > 0 {
+            seg.StartSec, seg.EndSec = seg.Words[0].Start, seg.Words[n-1].End
+        }
+        t.Segments = []Segment{seg}
+    }
+    return t
+}
+
+// dgSpeaker names Deepgram's zero-based speaker index the way the diarizers do.
+func dgSpeaker(i *int) string {
+    if i == nil {
+        return ""
+    }
+    return fmt.Sprintf("Speaker %d", *i+1)
+}
+
+func dgWords(ws []dgWord) []Word {
+    if len(ws) == 0 {
+        return nil
+    }
+    out := make([]Word, 0, len(ws))
+    for _, w := range ws {
+        text := w.PunctuatedWord
+        if text == "" {
+            text = w.Word
+        }
+        out = append(out, Word{Start: w.Start, End: w.End, Text: text, Probability: w.Confidence})
+    }
+    return out
+}
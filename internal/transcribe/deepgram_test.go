@@ -0,0 +1,170 @@
+package transcribe
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "net/http"
+    "net/http/httptest"
+    "os"
+    "path/filepath"
+    "testing"
+    "time"
+)
+
+// deepgramServer stands in for the Deepgram API, replying to /v1/listen with handler.
+func deepgramServer(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+    t.Helper()
+    srv := httptest.NewServer(handler)
+    t.Cleanup(srv.Close)
+    return srv
+}
+
+func testAudio(t *testing.T) string {
+    t.Helper()
+    p := filepath.Join(t.TempDir(), "audio.wav")
+    if err := os.WriteFile(p, []byte("RIFF....WAVE"), 0o644); err != nil {
+        t.Fatal(err)
+    }
+    return p
+}
+
+func TestDeepgramTranscribe(t *testing.T) {
+    fixture, err := os.ReadFile("testdata/deepgram_prerecorded.json")
+    if err != nil {
+        t.Fatal(err)
+    }
+    srv := deepgramServer(t, func(w http.ResponseWriter, r *http.Request) {
+        if r.Method != http.MethodPost || r.URL.Path != "/v1/listen" {
+            t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+        }
+        if got := r.Header.Get("Authorization"); got != "Token test-key" {
+            t.Errorf("Authorization = %q", got)
+        }
+        if got := r.Header.Get("Content-Type"); got != "audio/wav" {
+            t.Errorf("Content-Type = %q", got)
+        }
+        q := r.URL.Query()
+        for k, want := range map[string]string{"model": "nova-3", "language": "en", "diarize": "true", "smart_format": "true", "utterances": "true"} {
+            if got := q.Get(k); got != want {
+                t.Errorf("query %s = %q, want %q", k, got, want)
+            }
+        }
+        w.Header().Set("Content-Type", "application/json")
+        w.Write(fixture)
+    })
+
+    be := NewDeepgramBackend(DeepgramOptions{
+        APIKey: "test-key", BaseURL: srv.URL, Model: "nova-3", Language: "en",
+        Diarize: true, SmartFormat: true, Utterances: true,
+    })
+    tr, err := be.Transcribe(context.Background(), testAudio(t))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if tr.Language != "en" || tr.Duration != 12480*time.Millisecond {
+        t.Errorf("language %q, duration %s", tr.Language, tr.Duration)
+    }
+    want := []struct {
+        start, end float64
+        text       string
+        speaker    string
+        words      int
+    }{
+        {0.32, 3.04, "Morning, everyone. Let's start with the release.", "Speaker 1", 7},
+        {4.4, 5.68, "It shipped on Tuesday.", "Speaker 2", 4},
+    }
+    if len(tr.Segments) != len(want) {
+        t.Fatalf("got %d segments, want %d", len(tr.Segments), len(want))
+    }
+    for i, w := range want {
+        s := tr.Segments[i]
+        if s.StartSec != w.start || s.EndSec != w.end || s.Text != w.text || s.Speaker != w.speaker || len(s.Words) != w.words {
+            t.Errorf("segment %d = %+v", i, s)
+        }
+    }
+    first := tr.Segments[0].Words[0]
+    if first != (Word{Start: 0.32, End: 0.8, Text: "Morning,", Probability: 0.99}) {
+        t.Errorf("first word = %+v", first)
+    }
+}
+
+func TestDeepgramWithoutUtterances(t *testing.T) {
+    fixture, err := os.ReadFile("testdata/deepgram_prerecorded.json")
+    if err != nil {
+        t.Fatal(err)
+    }
+    var dr dgResp
+    if err := json.Unmarshal(fixture, &dr); err != nil {
+        t.Fatal(err)
+    }
+    dr.Results.Utterances = nil
+    tr := dr.transcript()
+    if len(tr.Segments) != 1 {
+        t.Fatalf("got %d segments, want 1", len(tr.Segments))
+    }
+    s := tr.Segments[0]
+    if s.StartSec != 0.32 || s.EndSec != 5.68 || s.Speaker != "" || len(s.Words) != 11 {
+        t.Errorf("segment = %+v", s)
+    }
+}
+
+func TestDeepgramErrors(t *testing.T) {
+    for _, c := range []struct {
+        status    int
+        kind      error
+        retryable bool
+    }{
+        {http.StatusUnauthorized, ErrAuthFailed, false},
+        {http.StatusTooManyRequests, ErrRateLimited, true},
+        {http.StatusBadGateway, ErrUnavailable, true},
+        {http.StatusServiceUnavailable, ErrUnavailable, true},
+    } {
+        calls := 0
+        srv := deepgramServer(t, func(w http.ResponseWriter, r *http.Request) {
+            calls++
+            http.Error(w, `{"err_code":"TEST","err_msg":"stand-in failure"}`, c.status)
+        })
+        be := NewDeepgramBackend(DeepgramOptions{
+            APIKey: "test-key", BaseURL: srv.URL,
+            Retry: RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond},
+        })
+        _, err := be.Transcribe(context.Background(), testAudio(t))
+        var he *HTTPError
+        if !errors.As(err, &he) || he.StatusCode != c.status || he.Backend != "deepgram" {
+            t.Errorf("%d: got error %v", c.status, err)
+            continue
+        }
+        if !errors.Is(err, c.kind) {
+            t.Errorf("%d: error %v is not %v", c.status, err, c.kind)
+        }
+        if IsRetryable(err) != c.retryable || shouldFallBack(err) != c.retryable {
+            t.Errorf("%d: retryable = %t, fall back = %t, want %t", c.status, IsRetryable(err), shouldFallBack(err), c.retryable)
+        }
+        // transient failures are retried, the rest fail on the first attempt
+        if wantCalls := map[bool]int{true: 2, false: 1}[c.retryable]; calls != wantCalls {
+            t.Errorf("%d: %d attempts, want %d", c.status, calls, wantCalls)
+        }
+    }
+}
+
+func TestDeepgramRetryThenSuccess(t *testing.T) {
+    calls := 0
+    srv := deepgramServer(t, func(w http.ResponseWriter, r *http.Request) {
+        calls++
+        if calls == 1 {
+            w.Header().Set("Retry-After", "0")
+            w.WriteHeader(http.StatusServiceUnavailable)
+            return
+        }
+        w.Write([]byte(`{"metadata":{"duration":1},"results":{"channels":[{"alternatives":[{"transcript":"ok","words":[]}]}]}}`))
+    })
+    be := NewDeepgramBackend(DeepgramOptions{BaseURL: srv.URL, Retry: RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}})
+    tr, err := be.Transcribe(context.Background(), testAudio(t))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if calls != 2 || len(tr.Segments) != 1 || tr.Segments[0].Text != "ok" {
+        t.Errorf("%d calls, transcript %+v", calls, tr)
+    }
+}
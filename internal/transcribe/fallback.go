@@ -0,0 +1,127 @@
+package transcribe
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "strings"
+    "sync"
+)
+
+// NamedBackend pairs a backend with the name it was selected by (e.g. "openai").
+type NamedBackend struct {
+    Name    string
+    Backend Backend
+    // Setup, when set, prepares the backend (e.g. installs a local model runtime). A
+    // fallback chain runs it only when the member is first needed.
+    Setup func() error
+}
+
+// fallbackBackend tries its members in order, moving on only when a member fails with an
+// availability problem (network error, rate limit/quota, 5xx). Other failures, such as a
+// rejected request or a local misconfiguration, are returned as-is.
+type fallbackBackend struct {
+    members    []NamedBackend
+    onFallback func(name string, err error)
+
+    mu       sync.Mutex
+    setUp    []bool
+    setupErr []error
+}
+
+// NewFallbackBackend returns a Backend trying members in order. onFallback, when set, is
+// called with each member that was skipped and why. The returned transcript's Backend
+// field names the member that produced it.
+func NewFallbackBackend(members []NamedBackend, onFallback func(name string, err error)) Backend {
+    return &fallbackBackend{
+        members:    members,
+        onFallback: onFallback,
+        setUp:      make([]bool, len(members)),
+        setupErr:   make([]error, len(members)),
+    }
+}
+
+// setup runs member i's Setup once and returns its result. A member that cannot be set
+// up counts as unavailable, so the chain moves on.
+func (f *fallbackBackend) setup(i int) error {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+    if !f.setUp[i] {
+        f.setUp[i] = true
+        if s := f.members[i].Setup; s != nil {
+            if err := s(); err != nil {
+                f.setupErr[i] = fmt.Errorf("%w (%w)", err, ErrUnavailable)
+            }
+        }
+    }
+    return f.setupErr[i]
+}
+
+func (f *fallbackBackend) Transcribe(ctx context.Context, audioPath string) (Transcript, error) {
+    var errs []error
+    for i, m := range f.members {
+        err := f.setup(i)
+        var tr Transcript
+        if err == nil {
+            tr, err = m.Backend.Transcribe(ctx, audioPath)
+        }
+        if err == nil {
+            tr.Backend = m.Name
+            return tr, nil
+        }
+        errs = append(errs, fmt.Errorf("%s: %w", m.Name, err))
+        if !shouldFallBack(err) || i == len(f.members)-1 || ctx.Err() != nil {
+            break
+        }
+        if f.onFallback != nil {
+            f.onFallback(m.Name, err)
+        }
+    }
+    return Transcript{}, errors.Join(errs...)
+}
+
+// shouldFallBack reports whether err means the backend is unavailable rather than the
+// request being bad: a rate limit or outage, or a network failure on the way to it.
+// Anything else, such as a missing model, a bad plugin path or a response that would not
+// decode, is a problem the user has to fix and is surfaced straight away.
+func shouldFallBack(err error) bool {
+    if errors.Is(err, ErrPermanent) {
+        return false
+    }
+    if errors.Is(err, ErrRateLimited) || errors.Is(err, ErrUnavailable) {
+        return true
+    }
+    return isTransportError(err)
+}
+
+func (f *fallbackBackend) Fingerprint() string {
+    parts := make([]string, 0, len(f.members))
+    for _, m := range f.members {
+        fp := m.Name
+        if p, ok := m.Backend.(Fingerprinter); ok {
+            fp = p.Fingerprint()
+        }
+        parts = append(parts, fp)
+    }
+    return "fallback[" + strings.Join(parts, " | ") + "]"
+}
+
+// OnProgress forwards fn to every member that reports progress.
+func (f *fallbackBackend) OnProgress(fn ProgressFunc) {
+    for _, m := range f.members {
+        if pr, ok := m.Backend.(ProgressReporter); ok {
+            pr.OnProgress(fn)
+        }
+    }
+}
+
+// Close closes every member holding resources.
+func (f *fallbackBackend) Close() error {
+    var errs []error
+    for _, m := range f.members {
+        if c, ok := m.Backend.(interface{ Close() error }); ok {
+            errs = append(errs, c.Close())
+        }
+    }
+    return errors.Join(errs...)
+}
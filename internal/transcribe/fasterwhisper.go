@@ -4,69 +4,200 @@ import (
     "context"
     _ "embed"
     "encoding/json"
+    "errors"
     "fmt"
     "os"
-    "os/exec"
-    "path/filepath"
     "strings"
+    "sync"
     "time"
 )
 
 //go:embed assets/faster_whisper.py
 var fwScript []byte
 
+// FasterWhisperOptions configures the local faster-whisper backend. Zero values leave
+// faster-whisper's own defaults in place.
+type FasterWhisperOptions struct {
+    Model          string
+    Device         string // auto|cpu|cuda
+    ComputeType    string // auto|int8|int8_float16|float16|float32
+    Language       string // e.g. "en"; empty auto-detects
+    Task           string // transcribe (default) or translate to English
+    BeamSize       int
+    VADFilter      bool   // skip non-speech with the built-in Silero VAD
+    InitialPrompt  string // vocabulary/context hint for the first window
+    Hotwords       string // terms to bias towards in every window (faster-whisper >= 1.0.2)
+    HotwordsPrompt string // used as the initial prompt instead when faster-whisper lacks hotwords; keep within WhisperPromptTokens
+    WordTimestamps bool   // also report per-word timings and probabilities
+}
+
+// fasterWhisperBackend keeps one helper process with the model loaded and reuses it
+// for every Transcribe call. Call Close when done to stop the process.
 type fasterWhisperBackend struct {
-    model  string
-    device string // auto|cpu|cuda
+    opts FasterWhisperOptions
+
+    mu       sync.Mutex // serialises requests; the helper handles one at a time
+    worker   *fwWorker
+    progress ProgressFunc
+}
+
+func NewFasterWhisperBackend(opts FasterWhisperOptions) Backend {
+    if opts.Device == "" { opts.Device = "auto" }
+    if opts.ComputeType == "" { opts.ComputeType = "auto" }
+    return &fasterWhisperBackend{opts: opts}
 }
 
-func NewFasterWhisperBackend(model, device string) Backend {
-    return &fasterWhisperBackend{model: model, device: device}
+func (f *fasterWhisperBackend) Fingerprint() string {
+    o := f.opts
+    return fmt.Sprintf("local model=%s device=%s compute=%s task=%s language=%s beam=%d vad=%t prompt=%q hotwords=%q words=%t",
+        o.Model, o.Device, o.ComputeType, o.Task, o.Language, o.BeamSize, o.VADFilter, o.InitialPrompt, o.Hotwords, o.WordTimestamps)
 }
 
-type fwOut struct {
+// OnProgress registers fn to be called as segments stream in from the helper.
+func (f *fasterWhisperBackend) OnProgress(fn ProgressFunc) { f.progress = fn }
+
+// fwInfo is the "info" event (and final result) of a streamed transcription.
+type fwInfo struct {
     Language string `json:"language"`
     Duration float64 `json:"duration"`
-    Segments []struct{
-        Start float64 `json:"start"`
-        End   float64 `json:"end"`
-        Text  string  `json:"text"`
-    } `json:"segments"`
+}
+
+type fwSegment struct {
+    Start float64 `json:"start"`
+    End   float64 `json:"end"`
+    Text  string  `json:"text"`
+    Words []struct {
+        Start       float64 `json:"start"`
+        End         float64 `json:"end"`
+        Word        string  `json:"word"`
+        Probability float64 `json:"probability"`
+    } `json:"words"`
 }
 
 func (f *fasterWhisperBackend) Transcribe(ctx context.Context, audioPath string) (Transcript, error) {
-    // Write embedded script to temp
-    dir := os.TempDir()
-    scriptPath := filepath.Join(dir, "mrp_faster_whisper.py")
-    if err := os.WriteFile(scriptPath, fwScript, 0o755); err != nil {
-        return Transcript{}, fmt.Errorf("write helper script: %w", err)
+    f.mu.Lock()
+    defer f.mu.Unlock()
+
+    // A worker that crashed mid-request is restarted once and the request replayed.
+    for attempt := 0; ; attempt++ {
+        w, err := f.ensureWorker(ctx)
+        if err != nil {
+            return Transcript{}, err
+        }
+        tr, err := f.transcribeWith(ctx, w, audioPath)
+        if err == nil {
+            return tr, nil
+        }
+        if errors.Is(err, errWorkerDied) {
+            f.worker.Close()
+            f.worker = nil
+            if attempt == 0 && ctx.Err() == nil {
+                continue
+            }
+        }
+        return Transcript{}, err
     }
-    defer os.Remove(scriptPath)
+}
 
-    device := f.device
-    if device == "" { device = "auto" }
-    // Call python3 helper
+// transcribeWith runs one streamed transcription on w, collecting segments as they arrive.
+func (f *fasterWhisperBackend) transcribeWith(ctx context.Context, w *fwWorker, audioPath string) (Transcript, error) {
+    start := time.Now()
+    var tr Transcript
+    var total float64
+    onEvent := func(ev fwResponse) error {
+        switch ev.Event {
+        case "info":
+            var in fwInfo
+            if err := json.Unmarshal(ev.Info, &in); err != nil {
+                return fmt.Errorf("parse helper info: %w", err)
+            }
+            total = in.Duration
+        case "segment":
+            var s fwSegment
+            if err := json.Unmarshal(ev.Segment, &s); err != nil {
+                return fmt.Errorf("parse helper segment: %w", err)
+            }
+            seg := Segment{StartSec: s.Start, EndSec: s.End, Text: strings.TrimSpace(s.Text)}
+            for _, w := range s.Words {
+                seg.Words = append(seg.Words, Word{Start: w.Start, End: w.End, Text: strings.TrimSpace(w.Word), Probability: w.Probability})
+            }
+            tr.Segments = append(tr.Segments, seg)
+            if f.progress != nil {
+                f.progress(Progress{ProcessedSec: s.End, TotalSec: total, Elapsed: time.Since(start)})
+            }
+        }
+        return nil
+    }
+    req := fwRequest{
+        Op:             "transcribe",
+        Audio:          audioPath,
+        Language:       f.opts.Language,
+        Task:           f.opts.Task,
+        BeamSize:       f.opts.BeamSize,
+        VADFilter:      f.opts.VADFilter,
+        InitialPrompt:  f.opts.InitialPrompt,
+        Hotwords:       f.opts.Hotwords,
+        HotwordsPrompt: f.opts.HotwordsPrompt,
+        WordTimestamps: f.opts.WordTimestamps,
+    }
+    raw, err := w.call(ctx, req, onEvent)
+    if err != nil {
+        return Transcript{}, err
+    }
+    var final fwInfo
+    if err := json.Unmarshal(raw, &final); err != nil {
+        return Transcript{}, fmt.Errorf("parse helper output: %w\n%s", err, string(raw))
+    }
+    tr.Language = final.Language
+    if f.opts.Task == TaskTranslate {
+        tr.OutputLanguage = "en"
+    }
+    tr.Duration = time.Duration(final.Duration*float64(time.Second))
+    tr.Device = w.deviceUsed
+    tr.ComputeType = w.computeType
+    if f.progress != nil {
+        f.progress(Progress{ProcessedSec: final.Duration, TotalSec: final.Duration, Elapsed: time.Since(start)})
+    }
+    return tr, nil
+}
+
+// ensureWorker returns a healthy worker, starting (or restarting) one as needed.
+func (f *fasterWhisperBackend) ensureWorker(ctx context.Context) (*fwWorker, error) {
+    if f.worker != nil && f.worker.alive() {
+        pctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+        _, err := f.worker.call(pctx, fwRequest{Op: "ping"}, nil)
+        cancel()
+        if err == nil {
+            return f.worker, nil
+        }
+        if ctx.Err() != nil {
+            return nil, ctx.Err()
+        }
+    }
+    if f.worker != nil {
+        f.worker.Close()
+        f.worker = nil
+    }
     py := os.Getenv("MRP_PY")
     if py == "" {
         py = "python3"
     }
-    cmd := exec.CommandContext(ctx, py, scriptPath, "--audio", audioPath, "--model", f.model, "--device", device)
-    cmd.Env = os.Environ()
-    out, err := cmd.Output()
+    w, err := startFWWorker(ctx, py, f.opts.Model, f.opts.Device, f.opts.ComputeType)
     if err != nil {
-        // try to capture stderr
-        if ee, ok := err.(*exec.ExitError); ok {
-            return Transcript{}, fmt.Errorf("faster-whisper failed: %s", strings.TrimSpace(string(ee.Stderr)))
-        }
-        return Transcript{}, fmt.Errorf("run helper: %w", err)
+        return nil, err
     }
-    var parsed fwOut
-    if err := json.Unmarshal(out, &parsed); err != nil {
-        return Transcript{}, fmt.Errorf("parse helper output: %w\n%s", err, string(out))
-    }
-    tr := Transcript{Language: parsed.Language, Duration: time.Duration(parsed.Duration*float64(time.Second))}
-    for _, s := range parsed.Segments {
-        tr.Segments = append(tr.Segments, Segment{StartSec: s.Start, EndSec: s.End, Text: strings.TrimSpace(s.Text)})
+    f.worker = w
+    return w, nil
+}
+
+// Close stops the helper process, if one is running.
+func (f *fasterWhisperBackend) Close() error {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+    if f.worker == nil {
+        return nil
     }
-    return tr, nil
+    err := f.worker.Close()
+    f.worker = nil
+    return err
 }
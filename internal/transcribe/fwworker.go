@@ -0,0 +1,221 @@
+package transcribe
+
+import (
+    "bufio"
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io"
+    "os"
+    "os/exec"
+    "path/filepath"
+    "time"
+)
+
+// errWorkerDied means the helper process exited while a request was in flight.
+var errWorkerDied = errors.New("faster-whisper worker exited")
+
+// fwRequest and fwResponse are the line-delimited JSON protocol spoken by
+// faster_whisper.py --serve. Every request gets exactly one response with the same ID.
+type fwRequest struct {
+    ID             int64  `json:"id"`
+    Op             string `json:"op"`
+    Audio          string `json:"audio,omitempty"`
+    Language       string `json:"language,omitempty"`
+    Task           string `json:"task,omitempty"`
+    BeamSize       int    `json:"beam_size,omitempty"`
+    VADFilter      bool   `json:"vad_filter,omitempty"`
+    InitialPrompt  string `json:"initial_prompt,omitempty"`
+    Hotwords       string `json:"hotwords,omitempty"`
+    HotwordsPrompt string `json:"hotwords_prompt,omitempty"`
+    WordTimestamps bool   `json:"word_timestamps,omitempty"`
+}
+
+type fwResponse struct {
+    ID      int64           `json:"id"`
+    Ready   bool            `json:"ready"`
+    Event   string          `json:"event"` // "info" or "segment" while a transcription streams
+    Info    json.RawMessage `json:"info"`
+    Segment json.RawMessage `json:"segment"`
+    Result  json.RawMessage `json:"result"`
+    Error   string          `json:"error"`
+}
+
+// fwWorker is one running `faster_whisper.py --serve` process with its model loaded.
+type fwWorker struct {
+    cmd         *exec.Cmd
+    stdin       io.WriteCloser
+    stdout      *bufio.Reader
+    done        chan struct{} // closed once the process has exited
+    nextID      int64
+    scriptPath  string
+    deviceUsed  string
+    computeType string
+}
+
+// startFWWorker launches the helper and waits for its ready line, which is only
+// written after the model has loaded (and possibly been downloaded).
+func startFWWorker(ctx context.Context, py, model, device, computeType string) (*fwWorker, error) {
+    scriptPath, err := writeFWScript()
+    if err != nil {
+        return nil, err
+    }
+    // The worker outlives the request that started it, so it is not bound to ctx.
+    cmd := exec.Command(py, scriptPath, "--serve", "--model", model, "--device", device, "--compute-type", computeType)
+    cmd.Env = os.Environ()
+    cmd.Stderr = os.Stderr
+    stdin, err := cmd.StdinPipe()
+    if err != nil {
+        os.Remove(scriptPath)
+        return nil, err
+    }
+    stdout, err := cmd.StdoutPipe()
+    if err != nil {
+        os.Remove(scriptPath)
+        return nil, err
+    }
+    if err := cmd.Start(); err != nil {
+        os.Remove(scriptPath)
+        return nil, fmt.Errorf("start faster-whisper worker: %w", err)
+    }
+    w := &fwWorker{
+        cmd:        cmd,
+        stdin:      stdin,
+        stdout:     bufio.NewReaderSize(stdout, 1<<20),
+        done:       make(chan struct{}),
+        scriptPath: scriptPath,
+    }
+    go func() {
+        cmd.Wait()
+        close(w.done)
+    }()
+
+    ready, err := w.readResponse(ctx)
+    if err != nil {
+        w.Close()
+        return nil, fmt.Errorf("faster-whisper worker did not start: %w", err)
+    }
+    if !ready.Ready {
+        w.Close()
+        return nil, fmt.Errorf("faster-whisper worker: unexpected greeting")
+    }
+    var greeting struct {
+        DeviceUsed  string `json:"device_used"`
+        ComputeType string `json:"compute_type"`
+    }
+    json.Unmarshal(ready.Result, &greeting)
+    w.deviceUsed = greeting.DeviceUsed
+    w.computeType = greeting.ComputeType
+    return w, nil
+}
+
+// writeFWScript writes the embedded helper to a private temp file.
+func writeFWScript() (string, error) {
+    f, err := os.CreateTemp("", "mrp_faster_whisper_*.py")
+    if err != nil {
+        return "", fmt.Errorf("write helper script: %w", err)
+    }
+    if _, err := f.Write(fwScript); err != nil {
+        f.Close()
+        os.Remove(f.Name())
+        return "", fmt.Errorf("write helper script: %w", err)
+    }
+    if err := f.Close(); err != nil {
+        os.Remove(f.Name())
+        return "", fmt.Errorf("write helper script: %w", err)
+    }
+    return filepath.Clean(f.Name()), nil
+}
+
+// alive reports whether the process is still running.
+func (w *fwWorker) alive() bool {
+    select {
+    case <-w.done:
+        return false
+    default:
+        return true
+    }
+}
+
+// call sends one request and waits for its final response, passing any streamed events
+// to onEvent (which may be nil). Cancelling ctx kills the worker, since the helper cannot
+// abort a transcription half-way.
+func (w *fwWorker) call(ctx context.Context, req fwRequest, onEvent func(fwResponse) error) (json.RawMessage, error) {
+    w.nextID++
+    req.ID = w.nextID
+    line, err := json.Marshal(req)
+    if err != nil {
+        return nil, err
+    }
+    if _, err := w.stdin.Write(append(line, '\n')); err != nil {
+        return nil, errWorkerDied
+    }
+    for {
+        resp, err := w.readResponse(ctx)
+        if err != nil {
+            return nil, err
+        }
+        if resp.ID != req.ID {
+            continue // stale reply to a request we gave up on
+        }
+        if resp.Event != "" {
+            if onEvent != nil {
+                if err := onEvent(resp); err != nil {
+                    return nil, err
+                }
+            }
+            continue
+        }
+        if resp.Error != "" {
+            return nil, fmt.Errorf("faster-whisper: %s", resp.Error)
+        }
+        return resp.Result, nil
+    }
+}
+
+// readResponse reads the next protocol line, giving up when ctx ends or the process exits.
+func (w *fwWorker) readResponse(ctx context.Context) (fwResponse, error) {
+    type lineOrErr struct {
+        line []byte
+        err  error
+    }
+    ch := make(chan lineOrErr, 1)
+    go func() {
+        line, err := w.stdout.ReadBytes('\n')
+        ch <- lineOrErr{line, err}
+    }()
+    select {
+    case <-ctx.Done():
+        w.Close()
+        return fwResponse{}, ctx.Err()
+    case r := <-ch:
+        if r.err != nil {
+            return fwResponse{}, errWorkerDied
+        }
+        var resp fwResponse
+        if err := json.Unmarshal(r.line, &resp); err != nil {
+            return fwResponse{}, fmt.Errorf("parse worker output: %w: %s", err, string(r.line))
+        }
+        if resp.Ready && resp.Result == nil {
+            // the greeting carries its fields at the top level
+            resp.Result = json.RawMessage(r.line)
+        }
+        return resp, nil
+    }
+}
+
+// Close asks the worker to exit by closing its stdin, and kills it if it lingers.
+func (w *fwWorker) Close() error {
+    w.stdin.Close()
+    select {
+    case <-w.done:
+    case <-time.After(3 * time.Second):
+        if w.cmd.Process != nil {
+            w.cmd.Process.Kill()
+        }
+        <-w.done
+    }
+    os.Remove(w.scriptPath)
+    return nil
+}
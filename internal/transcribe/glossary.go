@@ -0,0 +1,87 @@
+package transcribe
+
+import (
+    "bufio"
+    "os"
+    "strings"
+)
+
+// WhisperPromptTokens is the prompt budget of Whisper-family decoders: half of the
+// 448-token text context. Both OpenAI and faster-whisper silently keep only the last
+// 224 tokens of a longer prompt, which would drop the first (most important) glossary
+// terms, so the terms are trimmed here instead.
+const WhisperPromptTokens = 224
+
+// LoadGlossary reads one term per line, skipping blank lines and # comments. For
+// "wrong => right" lines, as used by --corrections dictionaries, the right-hand side
+// is the term, so one file can serve both.
+func LoadGlossary(path string) ([]string, error) {
+    f, err := os.Open(path)
+    if err != nil {
+        return nil, err
+    }
+    defer f.Close()
+    var terms []string
+    sc := bufio.NewScanner(f)
+    for sc.Scan() {
+        line := strings.TrimSpace(sc.Text())
+        if line == "" || strings.HasPrefix(line, "#") {
+            continue
+        }
+        if _, right, found := strings.Cut(line, "=>"); found {
+            line = strings.TrimSpace(right)
+        }
+        terms = append(terms, line)
+    }
+    return terms, sc.Err()
+}
+
+// MergeTerms concatenates term lists, dropping blanks and case-insensitive duplicates
+// while keeping the first spelling seen.
+func MergeTerms(lists ...[]string) []string {
+    seen := map[string]bool{}
+    var out []string
+    for _, l := range lists {
+        for _, t := range l {
+            t = strings.TrimSpace(t)
+            k := strings.ToLower(t)
+            if t == "" || seen[k] {
+                continue
+            }
+            seen[k] = true
+            out = append(out, t)
+        }
+    }
+    return out
+}
+
+// estimateTokens approximates the BPE token count of s. Proper nouns and jargon split
+// into short pieces, so this errs high: roughly one token per three characters.
+func estimateTokens(s string) int {
+    n := len([]rune(s))
+    return (n + 2) / 3
+}
+
+// GlossaryPrompt appends terms to prompt as a comma-separated list, stopping before the
+// estimated size exceeds maxTokens, and reports how many terms fit. Earlier terms win,
+// so list the most important first.
+func GlossaryPrompt(prompt string, terms []string, maxTokens int) (string, int) {
+    out := strings.TrimSpace(prompt)
+    sep := ""
+    if out != "" {
+        sep = " "
+        if !strings.ContainsAny(out[len(out)-1:], ".!?:;,") {
+            sep = ". "
+        }
+    }
+    n := 0
+    for _, t := range terms {
+        if estimateTokens(out+sep+t) > maxTokens {
+            break
+        }
+        out += sep + t
+        sep = ", "
+        n++
+    }
+    return out, n
+}
@@ -0,0 +1,170 @@
+package transcribe
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "io"
+    "math/rand/v2"
+    "net"
+    "net/http"
+    "strconv"
+    "strings"
+    "syscall"
+    "time"
+)
+
+// Error kinds reported by the HTTP backends. Use errors.Is to classify a failure.
+var (
+    ErrRateLimited     = errors.New("rate limited")
+    ErrAuthFailed      = errors.New("authentication failed")
+    ErrPayloadTooLarge = errors.New("payload too large")
+    ErrUnavailable     = errors.New("service unavailable")
+    ErrBadRequest      = errors.New("request rejected")
+    ErrPermanent       = errors.New("permanent failure") // the backend says trying again or elsewhere will not help
+)
+
+// HTTPError is a non-success response from a transcription API.
+type HTTPError struct {
+    Backend    string
+    StatusCode int
+    Body       string
+    RetryAfter time.Duration // zero when the server did not say
+    Kind       error         // one of the Err* kinds above
+}
+
+func (e *HTTPError) Error() string {
+    return fmt.Sprintf("%s http %d: %s", e.Backend, e.StatusCode, strings.TrimSpace(e.Body))
+}
+
+func (e *HTTPError) Unwrap() error { return e.Kind }
+
+// RetryPolicy controls how HTTP backends retry transient failures.
+type RetryPolicy struct {
+    MaxAttempts int           // total attempts including the first; values below 1 mean 1
+    BaseDelay   time.Duration // first backoff step, doubled on each retry
+    MaxDelay    time.Duration // cap for the computed backoff (Retry-After may exceed it)
+    // OnRetry, when set, is called before sleeping ahead of the next attempt.
+    OnRetry func(attempt int, delay time.Duration, err error)
+}
+
+// DefaultRetryPolicy retries up to three times with 1s, 2s, 4s (jittered) pauses.
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 4, BaseDelay: time.Second, MaxDelay: 30 * time.Second}
+
+// IsRetryable reports whether err is a transient failure worth another attempt.
+func IsRetryable(err error) bool {
+    if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrPermanent) {
+        return false
+    }
+    var he *HTTPError
+    if errors.As(err, &he) {
+        return errors.Is(he.Kind, ErrRateLimited) || errors.Is(he.Kind, ErrUnavailable)
+    }
+    return isTransportError(err)
+}
+
+// isTransportError reports whether err is a network failure that may well not happen
+// again: a timeout, a dropped or refused connection, a response cut short, or a DNS
+// lookup that failed temporarily. A bad URL, an unsupported scheme or a TLS certificate
+// error fails the same way every time and is not one.
+func isTransportError(err error) bool {
+    if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+        errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+        return true
+    }
+    var dns *net.DNSError
+    if errors.As(err, &dns) {
+        return dns.IsTimeout || dns.IsTemporary
+    }
+    var ne net.Error
+    return errors.As(err, &ne) && ne.Timeout()
+}
+
+// classifyStatus maps an HTTP status code onto an error kind.
+func classifyStatus(code int) error {
+    switch {
+    case code == http.StatusTooManyRequests:
+        return ErrRateLimited
+    case code == http.StatusUnauthorized || code == http.StatusForbidden:
+        return ErrAuthFailed
+    case code == http.StatusRequestEntityTooLarge:
+        return ErrPayloadTooLarge
+    case code == http.StatusRequestTimeout || code == http.StatusTooEarly || code >= 500:
+        return ErrUnavailable
+    default:
+        return ErrBadRequest
+    }
+}
+
+// parseRetryAfter understands both the delta-seconds and HTTP-date forms.
+func parseRetryAfter(v string) time.Duration {
+    v = strings.TrimSpace(v)
+    if v == "" {
+        return 0
+    }
+    if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+        return time.Duration(secs) * time.Second
+    }
+    if t, err := http.ParseTime(v); err == nil {
+        if d := time.Until(t); d > 0 {
+            return d
+        }
+    }
+    return 0
+}
+
+// backoff returns the jittered delay before retry number attempt (1-based).
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+    base := p.BaseDelay
+    if base <= 0 { base = time.Second }
+    d := base << (attempt - 1)
+    if p.MaxDelay > 0 && (d > p.MaxDelay || d <= 0) {
+        d = p.MaxDelay
+    }
+    // equal jitter: keep half the step, randomise the rest
+    return d/2 + rand.N(d/2+1)
+}
+
+// doWithRetry sends the request built by newReq until it succeeds, fails permanently, or the
+// policy runs out of attempts. newReq is called once per attempt so bodies can be replayed.
+// On success the caller owns the response body.
+func doWithRetry(ctx context.Context, hc *http.Client, p RetryPolicy, backend string, newReq func() (*http.Request, error)) (*http.Response, error) {
+    attempts := p.MaxAttempts
+    if attempts < 1 { attempts = 1 }
+    for attempt := 1; ; attempt++ {
+        req, err := newReq()
+        if err != nil {
+            return nil, err
+        }
+        resp, err := hc.Do(req)
+        if err == nil && resp.StatusCode < 300 {
+            return resp, nil
+        }
+        var retryAfter time.Duration
+        if err == nil {
+            b, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+            resp.Body.Close()
+            retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+            err = &HTTPError{Backend: backend, StatusCode: resp.StatusCode, Body: string(b), RetryAfter: retryAfter, Kind: classifyStatus(resp.StatusCode)}
+        } else if ctx.Err() != nil {
+            return nil, ctx.Err()
+        }
+        if attempt >= attempts || !IsRetryable(err) {
+            return nil, err
+        }
+        delay := p.backoff(attempt)
+        if retryAfter > delay {
+            delay = retryAfter
+        }
+        if p.OnRetry != nil {
+            p.OnRetry(attempt, delay, err)
+        }
+        t := time.NewTimer(delay)
+        select {
+        case <-ctx.Done():
+            t.Stop()
+            return nil, ctx.Err()
+        case <-t.C:
+        }
+    }
+}
@@ -0,0 +1,67 @@
+package transcribe
+
+import (
+    "context"
+    "crypto/x509"
+    "errors"
+    "fmt"
+    "io"
+    "net"
+    "net/http"
+    "net/url"
+    "os"
+    "syscall"
+    "testing"
+    "time"
+)
+
+func TestIsRetryable(t *testing.T) {
+    urlErr := func(err error) error { return &url.Error{Op: "Post", URL: "https://api.example.com/v1", Err: err} }
+    for _, c := range []struct {
+        name string
+        err  error
+        want bool
+    }{
+        {"rate limited", &HTTPError{StatusCode: 429, Kind: ErrRateLimited}, true},
+        {"server error", &HTTPError{StatusCode: 503, Kind: ErrUnavailable}, true},
+        {"bad request", &HTTPError{StatusCode: 400, Kind: ErrBadRequest}, false},
+        {"auth", &HTTPError{StatusCode: 401, Kind: ErrAuthFailed}, false},
+        {"connection reset", urlErr(&net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}), true},
+        {"connection refused", urlErr(&net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}), true},
+        {"timeout", urlErr(&net.OpError{Op: "dial", Net: "tcp", Err: os.ErrDeadlineExceeded}), true},
+        {"truncated body", fmt.Errorf("read response: %w", io.ErrUnexpectedEOF), true},
+        {"server closed connection", urlErr(io.EOF), true},
+        {"dns temporary", urlErr(&net.DNSError{Err: "server misbehaving", Name: "api.example.com", IsTemporary: true}), true},
+        {"dns no such host", urlErr(&net.DNSError{Err: "no such host", Name: "api.example.com", IsNotFound: true}), false},
+        {"unsupported scheme", urlErr(errors.New(`unsupported protocol scheme "htp"`)), false},
+        {"tls certificate", urlErr(x509.UnknownAuthorityError{}), false},
+        {"hostname mismatch", urlErr(x509.HostnameError{Certificate: &x509.Certificate{}, Host: "api.example.com"}), false},
+        {"request building", errors.New("net/http: nil Context"), false},
+        {"permanent", fmt.Errorf("plugin x: model not found (%w)", ErrPermanent), false},
+        {"canceled", urlErr(context.Canceled), false},
+    } {
+        if got := IsRetryable(c.err); got != c.want {
+            t.Errorf("%s: IsRetryable(%v) = %t, want %t", c.name, c.err, got, c.want)
+        }
+    }
+}
+
+func TestDoWithRetryGivesUpOnPermanentTransportErrors(t *testing.T) {
+    for _, endpoint := range []string{"htp://api.example.com/v1", "://missing-scheme"} {
+        calls := 0
+        _, err := doWithRetry(context.Background(), http.DefaultClient, RetryPolicy{MaxAttempts: 4, BaseDelay: time.Millisecond}, "test", func() (*http.Request, error) {
+            calls++
+            u, err := url.Parse(endpoint)
+            if err != nil {
+                return nil, err
+            }
+            return &http.Request{Method: http.MethodGet, URL: u, Header: http.Header{}}, nil
+        })
+        if err == nil {
+            t.Errorf("%s: expected an error", endpoint)
+        }
+        if calls != 1 {
+            t.Errorf("%s: %d attempts, want 1 (%v)", endpoint, calls, err)
+        }
+    }
+}
@@ -8,23 +8,136 @@ import (
     "io"
     "mime/multipart"
     "net/http"
+    "net/url"
     "os"
     "path/filepath"
+    "strings"
     "time"
 )
 
+// DefaultOpenAIBaseURL is the API root used when OpenAIOptions.BaseURL is empty.
+const DefaultOpenAIBaseURL = "https://api.openai.com/v1"
+
+// OpenAIOptions configures the OpenAI backend. Any server implementing the
+// /audio/transcriptions protocol (Azure OpenAI, LocalAI, speaches, ...) can be
+// targeted by pointing BaseURL at its API root.
+type OpenAIOptions struct {
+    APIKey  string            // sent as a bearer token when set
+    Model   string
+    BaseURL string            // API root; query parameters (e.g. Azure's api-version) are preserved
+    Headers map[string]string // extra request headers, e.g. Azure's api-key
+    Task    string            // transcribe (default) or translate, which uses /audio/translations
+    Prompt  string            // vocabulary/context hint; keep within WhisperPromptTokens
+    Retry   RetryPolicy
+}
+
 // OpenAI speech-to-text via audio.transcriptions
 type openAIBackend struct {
-    apiKey string
-    model  string
+    apiKey    string
+    model     string
+    endpoint  string
+    headers   map[string]string
+    official  bool // talking to OpenAI or Azure OpenAI rather than a compatible server
+    translate bool
+    prompt    string
+    retry     RetryPolicy
+}
+
+func NewOpenAIBackend(opts OpenAIOptions) (Backend, error) {
+    base := strings.TrimSpace(opts.BaseURL)
+    if base == "" {
+        base = DefaultOpenAIBaseURL
+    }
+    u, err := url.Parse(base)
+    if err != nil || u.Scheme == "" || u.Host == "" {
+        return nil, fmt.Errorf("invalid openai base url %q", base)
+    }
+    official := IsOfficialOpenAIURL(base)
+    translate := false
+    switch opts.Task {
+    case "", TaskTranscribe:
+        u.Path = strings.TrimSuffix(u.Path, "/") + "/audio/transcriptions"
+    case TaskTranslate:
+        translate = true
+        u.Path = strings.TrimSuffix(u.Path, "/") + "/audio/translations"
+    default:
+        return nil, fmt.Errorf("invalid openai task %q (want transcribe or translate)", opts.Task)
+    }
+    return &openAIBackend{
+        apiKey:    opts.APIKey,
+        model:     opts.Model,
+        endpoint:  u.String(),
+        headers:   opts.Headers,
+        official:  official,
+        translate: translate,
+        prompt:    opts.Prompt,
+        retry:     opts.Retry,
+    }, nil
+}
+
+// IsOfficialOpenAIURL reports whether base points at OpenAI or Azure OpenAI, both of
+// which require credentials. Self-hosted compatible servers usually do not.
+func IsOfficialOpenAIURL(base string) bool {
+    if strings.TrimSpace(base) == "" {
+        return true
+    }
+    u, err := url.Parse(base)
+    if err != nil {
+        return false
+    }
+    host := strings.ToLower(u.Hostname())
+    return host == "api.openai.com" || strings.HasSuffix(host, ".openai.azure.com")
 }
 
-func NewOpenAIBackend(apiKey, model string) Backend {
-    return &openAIBackend{apiKey: apiKey, model: model}
+func (o *openAIBackend) Fingerprint() string {
+    fp := fmt.Sprintf("openai endpoint=%s model=%s", o.endpoint, o.model)
+    if o.prompt != "" {
+        fp += fmt.Sprintf(" prompt=%q", o.prompt)
+    }
+    return fp
 }
 
+// MaxUploadBytes is the documented 25 MB limit of the OpenAI and Azure audio endpoints.
+// Compatible servers set their own limits, so no chunking is forced for them.
+func (o *openAIBackend) MaxUploadBytes() int64 {
+    if !o.official {
+        return 0
+    }
+    return 25 * 1024 * 1024
+}
+
+// openAIResp covers both the plain json and the verbose_json response shapes.
+// Models that only return text (e.g. gpt-4o-mini-transcribe) leave everything but Text empty.
 type openAIResp struct {
-    Text string `json:"text"`
+    Text     string  `json:"text"`
+    Language string  `json:"language"`
+    Duration float64 `json:"duration"`
+    Segments []struct {
+        Start float64 `json:"start"`
+        End   float64 `json:"end"`
+        Text  string  `json:"text"`
+    } `json:"segments"`
+    Words []struct {
+        Start float64 `json:"start"`
+        End   float64 `json:"end"`
+        Word  string  `json:"word"`
+    } `json:"words"`
+}
+
+// supportsVerboseJSON reports whether the model accepts response_format=verbose_json
+// and timestamp_granularities. OpenAI's gpt-4o transcribe models reject it with a 400;
+// whisper-1 and the whisper models served by compatible servers accept it.
+func (o *openAIBackend) supportsVerboseJSON() bool {
+    return !strings.HasPrefix(strings.ToLower(o.model), "gpt-")
+}
+
+// granularities lists the timestamp_granularities to request. The translations
+// endpoint does not accept the parameter.
+func (o *openAIBackend) granularities() []string {
+    if o.translate {
+        return nil
+    }
+    return []string{"segment", "word"}
 }
 
 func (o *openAIBackend) Transcribe(ctx context.Context, audioPath string) (Transcript, error) {
@@ -41,8 +154,26 @@ func (o *openAIBackend) Transcribe(ctx context.Context, audioPath string) (Trans
     if err := mw.WriteField("model", o.model); err != nil {
         return Transcript{}, err
     }
-    // response_format: json? But default returns text. Use verbose_json not supported here.
-    // We'll request plain text and post-process as a single segment.
+    if o.prompt != "" {
+        if err := mw.WriteField("prompt", o.prompt); err != nil {
+            return Transcript{}, err
+        }
+    }
+    // verbose_json gives us segment timings, language and duration; other models get plain json.
+    if o.supportsVerboseJSON() {
+        if err := mw.WriteField("response_format", "verbose_json"); err != nil {
+            return Transcript{}, err
+        }
+        for _, g := range o.granularities() {
+            if err := mw.WriteField("timestamp_granularities[]", g); err != nil {
+                return Transcript{}, err
+            }
+        }
+    } else {
+        if err := mw.WriteField("response_format", "json"); err != nil {
+            return Transcript{}, err
+        }
+    }
 
     fw, err := mw.CreateFormFile("file", filepath.Base(audioPath))
     if err != nil {
@@ -55,29 +186,56 @@ func (o *openAIBackend) Transcribe(ctx context.Context, audioPath string) (Trans
         return Transcript{}, err
     }
 
-    req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.openai.com/v1/audio/transcriptions", &body)
-    if err != nil {
-        return Transcript{}, err
-    }
-    req.Header.Set("Authorization", "Bearer "+o.apiKey)
-    req.Header.Set("Content-Type", mw.FormDataContentType())
-
+    payload := body.Bytes()
     hc := &http.Client{Timeout: 60 * time.Minute}
-    resp, err := hc.Do(req)
+    resp, err := doWithRetry(ctx, hc, o.retry, "openai", func() (*http.Request, error) {
+        req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.endpoint, bytes.NewReader(payload))
+        if err != nil {
+            return nil, err
+        }
+        if o.apiKey != "" {
+            req.Header.Set("Authorization", "Bearer "+o.apiKey)
+        }
+        for k, v := range o.headers {
+            req.Header.Set(k, v)
+        }
+        req.Header.Set("Content-Type", mw.FormDataContentType())
+        return req, nil
+    })
     if err != nil {
         return Transcript{}, err
     }
     defer resp.Body.Close()
-    if resp.StatusCode >= 300 {
-        b, _ := io.ReadAll(resp.Body)
-        return Transcript{}, fmt.Errorf("openai http %d: %s", resp.StatusCode, string(b))
-    }
     var or openAIResp
     if err := json.NewDecoder(resp.Body).Decode(&or); err != nil {
         return Transcript{}, err
     }
-    // We didn't get segment timings. For now, return as a single segment.
-    t := Transcript{Language: "", Segments: []Segment{{StartSec: 0, EndSec: 0, Text: or.Text}}, Duration: 0}
-    return t, nil
+    tr := or.transcript()
+    if o.translate {
+        // verbose_json reports the output language here ("english"), not the spoken one.
+        tr.Language = ""
+        tr.OutputLanguage = "en"
+    }
+    return tr, nil
 }
 
+// transcript maps the decoded response into a Transcript, falling back to a single
+// untimed segment when the model returned text only.
+func (or openAIResp) transcript() Transcript {
+    t := Transcript{
+        Language: or.Language,
+        Duration: time.Duration(or.Duration * float64(time.Second)),
+    }
+    for _, s := range or.Segments {
+        t.Segments = append(t.Segments, Segment{StartSec: s.Start, EndSec: s.End, Text: strings.TrimSpace(s.Text)})
+    }
+    if len(t.Segments) == 0 {
+        t.Segments = []Segment{{StartSec: 0, EndSec: 0, Text: or.Text}}
+    }
+    words := make([]Word, 0, len(or.Words))
+    for _, w := range or.Words {
+        words = append(words, Word{Start: w.Start, End: w.End, Text: strings.TrimSpace(w.Word)})
+    }
+    attachWords(t.Segments, words)
+    return t
+}
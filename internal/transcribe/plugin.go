@@ -0,0 +1,249 @@
+package transcribe
+
+import (
+    "bufio"
+    "context"
+    "encoding/json"
+    "fmt"
+    "os"
+    "os/exec"
+    "path/filepath"
+    "sort"
+    "strings"
+    "time"
+)
+
+// Plugin backends are executables named mrp-backend-<name>, found in ~/.mrp/plugins or on
+// PATH. mrp starts one process per transcription and exchanges one JSON object per line:
+// requests on the plugin's stdin, replies on its stdout; stderr is passed through. The
+// message flow is hello → capabilities, transcribe → segment/progress* → result|error,
+// then stdin is closed. See README.md ("Backend plugins") for the full schema.
+const PluginProtocolVersion = 1
+
+const pluginPrefix = "mrp-backend-"
+
+// pluginExitGrace is how long a plugin gets to exit once its stdin is closed before it
+// is killed.
+const pluginExitGrace = 5 * time.Second
+
+// PluginDir returns ~/.mrp/plugins, searched before PATH.
+func PluginDir() string {
+    home, err := os.UserHomeDir()
+    if err != nil {
+        return ""
+    }
+    return filepath.Join(home, ".mrp", "plugins")
+}
+
+// FindPlugin returns the executable implementing backend name, if any.
+func FindPlugin(name string) (string, bool) {
+    exe := pluginPrefix + name
+    if dir := PluginDir(); dir != "" {
+        p := filepath.Join(dir, exe)
+        if fi, err := os.Stat(p); err == nil && !fi.IsDir() && fi.Mode()&0o111 != 0 {
+            return p, true
+        }
+    }
+    if p, err := exec.LookPath(exe); err == nil {
+        return p, true
+    }
+    return "", false
+}
+
+// ListPlugins returns the names of all discoverable plugin backends.
+func ListPlugins() []string {
+    seen := map[string]bool{}
+    dirs := filepath.SplitList(os.Getenv("PATH"))
+    if dir := PluginDir(); dir != "" {
+        dirs = append([]string{dir}, dirs...)
+    }
+    for _, dir := range dirs {
+        matches, _ := filepath.Glob(filepath.Join(dir, pluginPrefix+"*"))
+        for _, m := range matches {
+            if fi, err := os.Stat(m); err == nil && !fi.IsDir() && fi.Mode()&0o111 != 0 {
+                seen[strings.TrimPrefix(filepath.Base(m), pluginPrefix)] = true
+            }
+        }
+    }
+    names := make([]string, 0, len(seen))
+    for n := range seen {
+        names = append(names, n)
+    }
+    sort.Strings(names)
+    return names
+}
+
+type pluginMsg struct {
+    Type     string            `json:"type"`
+    ID       int64             `json:"id,omitempty"`
+    Protocol int               `json:"protocol,omitempty"`
+    Audio    string            `json:"audio,omitempty"`
+    Options  map[string]string `json:"options,omitempty"`
+
+    // replies
+    Name    string `json:"name,omitempty"`
+    Segment *struct {
+        Start   float64 `json:"start"`
+        End     float64 `json:"end"`
+        Text    string  `json:"text"`
+        Speaker string  `json:"speaker"`
+        Words   []struct {
+            Start       float64 `json:"start"`
+            End         float64 `json:"end"`
+            Text        string  `json:"text"`
+            Probability float64 `json:"probability"`
+        } `json:"words"`
+    } `json:"segment,omitempty"`
+    Processed      float64 `json:"processed,omitempty"`
+    Total          float64 `json:"total,omitempty"`
+    Language       string  `json:"language,omitempty"`
+    OutputLanguage string  `json:"output_language,omitempty"`
+    Duration       float64 `json:"duration,omitempty"`
+    Message        string  `json:"message,omitempty"`
+    Retryable      bool    `json:"retryable,omitempty"`
+}
+
+// pluginBackend drives an external mrp-backend-<name> executable.
+type pluginBackend struct {
+    name     string
+    path     string
+    options  map[string]string
+    progress ProgressFunc
+}
+
+// NewPluginBackend returns a Backend running the plugin at path. options are passed through
+// verbatim in every transcribe request.
+func NewPluginBackend(name, path string, options map[string]string) Backend {
+    return &pluginBackend{name: name, path: path, options: options}
+}
+
+func (p *pluginBackend) Fingerprint() string {
+    keys := make([]string, 0, len(p.options))
+    for k := range p.options {
+        keys = append(keys, k)
+    }
+    sort.Strings(keys)
+    var b strings.Builder
+    fmt.Fprintf(&b, "plugin name=%s", p.name)
+    for _, k := range keys {
+        fmt.Fprintf(&b, " %s=%q", k, p.options[k])
+    }
+    return b.String()
+}
+
+// OnProgress registers fn for the plugin's progress messages.
+func (p *pluginBackend) OnProgress(fn ProgressFunc) { p.progress = fn }
+
+func (p *pluginBackend) Transcribe(ctx context.Context, audioPath string) (Transcript, error) {
+    cmd := exec.CommandContext(ctx, p.path)
+    cmd.Env = os.Environ()
+    cmd.Stderr = os.Stderr
+    stdin, err := cmd.StdinPipe()
+    if err != nil {
+        return Transcript{}, err
+    }
+    stdout, err := cmd.StdoutPipe()
+    if err != nil {
+        return Transcript{}, err
+    }
+    if err := cmd.Start(); err != nil {
+        return Transcript{}, fmt.Errorf("start plugin %s: %w", p.name, err)
+    }
+    defer func() {
+        stdin.Close()
+        done := make(chan struct{})
+        go func() {
+            cmd.Wait()
+            close(done)
+        }()
+        t := time.NewTimer(pluginExitGrace)
+        defer t.Stop()
+        select {
+        case <-done:
+        case <-t.C:
+            // a plugin ignoring EOF must not hold the run until the context deadline
+            cmd.Process.Kill()
+            <-done
+        }
+    }()
+
+    enc := json.NewEncoder(stdin)
+    dec := bufio.NewScanner(stdout)
+    dec.Buffer(make([]byte, 0, 64*1024), 16<<20)
+    read := func() (pluginMsg, error) {
+        if !dec.Scan() {
+            if err := dec.Err(); err != nil {
+                return pluginMsg{}, err
+            }
+            if ctx.Err() != nil {
+                return pluginMsg{}, ctx.Err()
+            }
+            return pluginMsg{}, fmt.Errorf("plugin %s exited before replying: %w", p.name, ErrUnavailable)
+        }
+        var m pluginMsg
+        if err := json.Unmarshal(dec.Bytes(), &m); err != nil {
+            return pluginMsg{}, fmt.Errorf("plugin %s sent invalid JSON: %w", p.name, err)
+        }
+        return m, nil
+    }
+
+    // handshake
+    if err := enc.Encode(pluginMsg{Type: "hello", Protocol: PluginProtocolVersion}); err != nil {
+        return Transcript{}, fmt.Errorf("plugin %s: %w", p.name, err)
+    }
+    caps, err := read()
+    if err != nil {
+        return Transcript{}, err
+    }
+    if caps.Type != "capabilities" {
+        return Transcript{}, fmt.Errorf("plugin %s: expected capabilities, got %q", p.name, caps.Type)
+    }
+    if caps.Protocol != PluginProtocolVersion {
+        return Transcript{}, fmt.Errorf("plugin %s speaks protocol %d, mrp speaks %d", p.name, caps.Protocol, PluginProtocolVersion)
+    }
+
+    // request
+    start := time.Now()
+    if err := enc.Encode(pluginMsg{Type: "transcribe", ID: 1, Audio: audioPath, Options: p.options}); err != nil {
+        return Transcript{}, fmt.Errorf("plugin %s: %w", p.name, err)
+    }
+    var tr Transcript
+    for {
+        m, err := read()
+        if err != nil {
+            return Transcript{}, err
+        }
+        switch m.Type {
+        case "segment":
+            if m.Segment == nil {
+                continue
+            }
+            seg := Segment{StartSec: m.Segment.Start, EndSec: m.Segment.End, Text: strings.TrimSpace(m.Segment.Text), Speaker: m.Segment.Speaker}
+            for _, w := range m.Segment.Words {
+                seg.Words = append(seg.Words, Word{Start: w.Start, End: w.End, Text: w.Text, Probability: w.Probability})
+            }
+            tr.Segments = append(tr.Segments, seg)
+        case "progress":
+            if p.progress != nil {
+                p.progress(Progress{ProcessedSec: m.Processed, TotalSec: m.Total, Elapsed: time.Since(start)})
+            }
+        case "result":
+            tr.Language = m.Language
+            tr.OutputLanguage = m.OutputLanguage
+            if tr.OutputLanguage == "" && p.options["task"] == TaskTranslate {
+                // Whisper-style translation always produces English
+                tr.OutputLanguage = "en"
+            }
+            tr.Duration = time.Duration(m.Duration * float64(time.Second))
+            return tr, nil
+        case "error":
+            kind := ErrPermanent
+            if m.Retryable {
+                kind = ErrUnavailable
+            }
+            return Transcript{}, fmt.Errorf("plugin %s: %s (%w)", p.name, m.Message, kind)
+        default:
+            // unknown message types are ignored so plugins can add extensions
+        }
+    }
+}
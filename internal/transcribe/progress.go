@@ -0,0 +1,37 @@
+package transcribe
+
+import "time"
+
+// Progress is a snapshot of how far a transcription has got.
+type Progress struct {
+    ProcessedSec float64       // audio transcribed so far
+    TotalSec     float64       // total audio length; 0 when unknown
+    Elapsed      time.Duration // wall time since the transcription started
+}
+
+// Fraction returns the completed share in [0,1], or 0 when the total is unknown.
+func (p Progress) Fraction() float64 {
+    if p.TotalSec <= 0 {
+        return 0
+    }
+    f := p.ProcessedSec / p.TotalSec
+    if f > 1 { f = 1 }
+    return f
+}
+
+// ETA extrapolates the remaining wall time from the rate so far; 0 when unknown.
+func (p Progress) ETA() time.Duration {
+    f := p.Fraction()
+    if f <= 0 || f >= 1 {
+        return 0
+    }
+    return time.Duration(float64(p.Elapsed) * (1 - f) / f)
+}
+
+// ProgressFunc receives progress updates; it is called from the transcribing goroutine.
+type ProgressFunc func(Progress)
+
+// ProgressReporter is implemented by backends that can report incremental progress.
+type ProgressReporter interface {
+    OnProgress(fn ProgressFunc)
+}
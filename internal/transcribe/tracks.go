@@ -0,0 +1,37 @@
+package transcribe
+
+import "sort"
+
+// MergeTracks combines transcripts of separate tracks of one recording (e.g. one
+// microphone per person) into a single transcript on the shared timeline. Every segment
+// is attributed to its track's label, which is what makes per-track diarization exact.
+// labels[i] names parts[i].
+func MergeTracks(parts []Transcript, labels []string) Transcript {
+    var out Transcript
+    for i, tr := range parts {
+        if out.Language == "" {
+            out.Language = tr.Language
+        }
+        if out.OutputLanguage == "" {
+            out.OutputLanguage = tr.OutputLanguage
+        }
+        if tr.Duration > out.Duration {
+            out.Duration = tr.Duration
+        }
+        if out.Device == "" {
+            out.Device, out.ComputeType = tr.Device, tr.ComputeType
+        }
+        if out.Backend == "" {
+            out.Backend = tr.Backend
+        }
+        for _, s := range tr.Segments {
+            s.Speaker = labels[i]
+            out.Segments = append(out.Segments, s)
+        }
+    }
+    // stable, so simultaneous segments keep track order
+    sort.SliceStable(out.Segments, func(a, b int) bool {
+        return out.Segments[a].StartSec < out.Segments[b].StartSec
+    })
+    return out
+}
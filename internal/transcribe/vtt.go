@@ -0,0 +1,57 @@
+package transcribe
+
+import (
+    "strconv"
+    "strings"
+)
+
+// parseVTT turns WebVTT cues into segments. Cue identifiers, NOTE/STYLE blocks and
+// cue settings are ignored; malformed timing lines are skipped.
+func parseVTT(vtt string) []Segment {
+    var segs []Segment
+    lines := strings.Split(strings.ReplaceAll(vtt, "\r\n", "\n"), "\n")
+    for i := 0; i < len(lines); i++ {
+        line := strings.TrimSpace(lines[i])
+        from, rest, found := strings.Cut(line, "-->")
+        if !found {
+            continue
+        }
+        start, ok1 := parseVTTTime(from)
+        // cue settings (align:start etc.) may follow the end time
+        fields := strings.Fields(rest)
+        if len(fields) == 0 {
+            continue
+        }
+        end, ok2 := parseVTTTime(fields[0])
+        if !ok1 || !ok2 {
+            continue
+        }
+        var text []string
+        for i+1 < len(lines) && strings.TrimSpace(lines[i+1]) != "" {
+            i++
+            text = append(text, strings.TrimSpace(lines[i]))
+        }
+        if len(text) == 0 {
+            continue
+        }
+        segs = append(segs, Segment{StartSec: start, EndSec: end, Text: strings.Join(text, " ")})
+    }
+    return segs
+}
+
+// parseVTTTime parses "hh:mm:ss.ttt" or "mm:ss.ttt".
+func parseVTTTime(s string) (float64, bool) {
+    parts := strings.Split(strings.TrimSpace(s), ":")
+    if len(parts) < 2 || len(parts) > 3 {
+        return 0, false
+    }
+    var total float64
+    for _, p := range parts {
+        v, err := strconv.ParseFloat(strings.Replace(p, ",", ".", 1), 64)
+        if err != nil {
+            return 0, false
+        }
+        total = total*60 + v
+    }
+    return total, true
+}
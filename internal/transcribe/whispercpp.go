@@ -0,0 +1,117 @@
+package transcribe
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "fmt"
+    "os"
+    "os/exec"
+    "path/filepath"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/zudsniper/meet-recording-processor/internal/media"
+)
+
+// whisper.cpp backend driving the native whisper-cli binary with a GGML model.
+type whisperCppBackend struct {
+    bin      string // whisper-cli executable (name on PATH or path)
+    model    string // path to a ggml-*.bin model
+    threads  int    // 0 leaves the binary's default
+    language string // "" or "auto" lets whisper.cpp detect
+}
+
+func NewWhisperCppBackend(bin, model string, threads int, language string) Backend {
+    if bin == "" {
+        bin = "whisper-cli"
+    }
+    return &whisperCppBackend{bin: bin, model: model, threads: threads, language: language}
+}
+
+func (w *whisperCppBackend) Fingerprint() string {
+    return fmt.Sprintf("whispercpp model=%s language=%s", w.model, w.language)
+}
+
+// wcppOut is the subset of whisper-cli's -oj output we use. Offsets are milliseconds.
+type wcppOut struct {
+    Result struct {
+        Language string `json:"language"`
+    } `json:"result"`
+    Transcription []struct {
+        Offsets struct {
+            From int64 `json:"from"`
+            To   int64 `json:"to"`
+        } `json:"offsets"`
+        Text string `json:"text"`
+    } `json:"transcription"`
+}
+
+func (w *whisperCppBackend) Transcribe(ctx context.Context, audioPath string) (Transcript, error) {
+    if w.model == "" {
+        return Transcript{}, fmt.Errorf("whisper.cpp: model path is required")
+    }
+    if _, err := os.Stat(w.model); err != nil {
+        return Transcript{}, fmt.Errorf("whisper.cpp model: %w", err)
+    }
+    bin, err := exec.LookPath(w.bin)
+    if err != nil {
+        return Transcript{}, fmt.Errorf("whisper.cpp binary %q not found: %w", w.bin, err)
+    }
+
+    dir, err := os.MkdirTemp("", "mrp-whispercpp-")
+    if err != nil {
+        return Transcript{}, err
+    }
+    defer os.RemoveAll(dir)
+    outPrefix := filepath.Join(dir, "out")
+
+    args := []string{"-m", w.model, "-f", audioPath, "-oj", "-of", outPrefix, "-np"}
+    if w.threads > 0 {
+        args = append(args, "-t", strconv.Itoa(w.threads))
+    }
+    lang := strings.TrimSpace(w.language)
+    if lang == "" {
+        lang = "auto"
+    }
+    args = append(args, "-l", lang)
+
+    cmd := exec.CommandContext(ctx, bin, args...)
+    cmd.Env = os.Environ()
+    var stderr bytes.Buffer
+    cmd.Stderr = &stderr
+    if err := cmd.Run(); err != nil {
+        if ctx.Err() != nil {
+            return Transcript{}, ctx.Err()
+        }
+        return Transcript{}, fmt.Errorf("whisper.cpp failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+    }
+
+    raw, err := os.ReadFile(outPrefix + ".json")
+    if err != nil {
+        return Transcript{}, fmt.Errorf("read whisper.cpp output: %w", err)
+    }
+    var parsed wcppOut
+    if err := json.Unmarshal(raw, &parsed); err != nil {
+        return Transcript{}, fmt.Errorf("parse whisper.cpp output: %w", err)
+    }
+
+    tr := Transcript{Language: parsed.Result.Language}
+    for _, s := range parsed.Transcription {
+        text := strings.TrimSpace(s.Text)
+        if text == "" {
+            continue
+        }
+        tr.Segments = append(tr.Segments, Segment{
+            StartSec: float64(s.Offsets.From) / 1000,
+            EndSec:   float64(s.Offsets.To) / 1000,
+            Text:     text,
+        })
+    }
+    // whisper.cpp does not report the input length; read it from the WAV header.
+    if info, err := media.ReadWAVInfo(audioPath); err == nil {
+        tr.Duration = time.Duration(info.DurationSec() * float64(time.Second))
+    }
+    return tr, nil
+}
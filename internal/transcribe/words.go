@@ -0,0 +1,49 @@
+package transcribe
+
+// attachWords distributes a transcript-wide word list onto the segments it belongs to,
+// for APIs that report words separately from segments. Each word goes to the segment
+// containing its midpoint, or the closest one when it falls in a gap.
+func attachWords(segs []Segment, words []Word) {
+    if len(segs) == 0 {
+        return
+    }
+    j := 0
+    for _, w := range words {
+        mid := (w.Start + w.End) / 2
+        for j < len(segs)-1 && mid >= segs[j].EndSec && mid-segs[j].EndSec >= segs[j+1].StartSec-mid {
+            j++
+        }
+        segs[j].Words = append(segs[j].Words, w)
+    }
+}
+
+// shiftWords returns a copy of words moved by offset seconds.
+func shiftWords(words []Word, offset float64) []Word {
+    if len(words) == 0 {
+        return nil
+    }
+    out := make([]Word, len(words))
+    for i, w := range words {
+        w.Start += offset
+        w.End += offset
+        out[i] = w
+    }
+    return out
+}
+
+// MapTimes rewrites every segment and word timestamp in tr through fn, e.g. to move a
+// transcript of trimmed audio back onto the original recording's timeline. Untimed
+// segments (EndSec 0) are left alone.
+func MapTimes(tr *Transcript, fn func(start, end float64) (float64, float64)) {
+    for i := range tr.Segments {
+        s := &tr.Segments[i]
+        if s.EndSec <= 0 {
+            continue
+        }
+        s.StartSec, s.EndSec = fn(s.StartSec, s.EndSec)
+        for j := range s.Words {
+            w := &s.Words[j]
+            w.Start, w.End = fn(w.Start, w.End)
+        }
+    }
+}